@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that the CICS and IMS bridge headers are laid out as described by the
+ * MQCIH and MQIIH structures, and can be parsed again.
+ */
+func TestBridgeHeaderLayout(t *testing.T) {
+
+	order := binary.ByteOrder(binary.BigEndian)
+	if ibmmq.MQENC_NATIVE&ibmmq.MQENC_INTEGER_MASK == ibmmq.MQENC_INTEGER_REVERSED {
+		order = binary.LittleEndian
+	}
+
+	cih := mqjms.NewCICSHeader()
+	cih.Format = ibmmq.MQFMT_STRING
+	cih.LinkType = ibmmq.MQCLT_TRANSACTION
+	cih.TransactionId = "TX01"
+	cih.Authenticator = "secret"
+
+	cihBytes := cih.Bytes()
+	assert.Equal(t, int(ibmmq.MQCIH_LENGTH_2), len(cihBytes))
+	assert.Equal(t, "CIH ", string(cihBytes[0:4]))
+	assert.Equal(t, ibmmq.MQCIH_VERSION_2, int32(order.Uint32(cihBytes[4:8])))
+	assert.Equal(t, ibmmq.MQCIH_LENGTH_2, int32(order.Uint32(cihBytes[8:12])))
+	assert.Equal(t, "MQSTR   ", string(cihBytes[20:28]))
+	assert.Equal(t, ibmmq.MQCUOWC_ONLY, int32(order.Uint32(cihBytes[44:48])))
+	assert.Equal(t, ibmmq.MQCGWI_DEFAULT, int32(order.Uint32(cihBytes[48:52])))
+	assert.Equal(t, ibmmq.MQCLT_TRANSACTION, int32(order.Uint32(cihBytes[52:56])))
+	assert.Equal(t, "secret  ", string(cihBytes[92:100]))
+	assert.Equal(t, "TX01", string(cihBytes[124:128]))
+
+	parsedCih, cihLen, err := mqjms.ParseCICSHeader(cihBytes, ibmmq.MQENC_NATIVE)
+	assert.Nil(t, err)
+	assert.Equal(t, int(ibmmq.MQCIH_LENGTH_2), cihLen)
+	assert.Equal(t, cih, parsedCih)
+
+	iih := mqjms.NewIMSHeader()
+	iih.Format = ibmmq.MQFMT_IMS_VAR_STRING
+	iih.LTermOverride = "LTERM1"
+	iih.TranState = mqjms.IMSHeader_TRANSTATE_IN_CONVERSATION
+	iih.CommitMode = mqjms.IMSHeader_COMMITMODE_SEND_THEN_COMMIT
+
+	iihBytes := iih.Bytes()
+	assert.Equal(t, int(ibmmq.MQIIH_LENGTH_1), len(iihBytes))
+	assert.Equal(t, "IIH ", string(iihBytes[0:4]))
+	assert.Equal(t, ibmmq.MQIIH_LENGTH_1, int32(order.Uint32(iihBytes[8:12])))
+	assert.Equal(t, "MQIMSVS ", string(iihBytes[20:28]))
+	assert.Equal(t, "LTERM1  ", string(iihBytes[32:40]))
+	assert.Equal(t, "C1C ", string(iihBytes[80:84]))
+
+	parsedIih, iihLen, err := mqjms.ParseIMSHeader(iihBytes, ibmmq.MQENC_NATIVE)
+	assert.Nil(t, err)
+	assert.Equal(t, int(ibmmq.MQIIH_LENGTH_1), iihLen)
+	assert.Equal(t, iih, parsedIih)
+
+	// A buffer that doesn't start with the header is rejected.
+	_, _, err = mqjms.ParseCICSHeader(iihBytes, ibmmq.MQENC_NATIVE)
+	assert.NotNil(t, err)
+
+}
+
+/*
+ * Test sending a CICS bridge message and reading the header back from the
+ * message that is received.
+ */
+func TestCICSBridgeMessage(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	cih := mqjms.NewCICSHeader()
+	cih.TransactionId = "TX01"
+	commarea := []byte("ACCOUNT=12345")
+
+	sendMsg := mqjms.CreateCICSBridgeMessage(context, cih, commarea)
+
+	gotFormat, propErr := sendMsg.GetStringProperty("JMS_IBM_Format")
+	assert.Nil(t, propErr)
+	assert.Equal(t, ibmmq.MQFMT_CICS, *gotFormat)
+
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch msg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		gotCih, gotData, errHeader := mqjms.GetCICSHeader(msg)
+		assert.Nil(t, errHeader)
+		assert.Equal(t, "TX01", gotCih.TransactionId)
+		assert.Equal(t, ibmmq.MQCLT_PROGRAM, gotCih.LinkType)
+		assert.Equal(t, commarea, gotData)
+
+		// The message doesn't have an IMS header.
+		_, _, errHeader = mqjms.GetIMSHeader(msg)
+		assert.NotNil(t, errHeader)
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test browsing the messages on a queue, and removing only the message that
+ * is under the browse cursor.
+ */
+func TestBrowserConsumeCurrent(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	msg1 := context.CreateTextMessageWithString("browse consume msg 1")
+	msg2 := context.CreateTextMessageWithString("browse consume msg 2")
+	msg3 := context.CreateTextMessageWithString("browse consume msg 3")
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+	errSend := producer.Send(queue, msg1)
+	assert.Nil(t, errSend)
+	errSend = producer.Send(queue, msg2)
+	assert.Nil(t, errSend)
+	errSend = producer.Send(queue, msg3)
+	assert.Nil(t, errSend)
+
+	browser, errBrowse := context.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if browser != nil {
+		defer browser.Close()
+	}
+
+	// Nothing has been browsed yet, so there is no message to consume.
+	noMsg, errConsume := browser.ConsumeCurrent()
+	assert.Nil(t, noMsg)
+	assert.NotNil(t, errConsume)
+
+	// Lock each message as it is browsed.
+	assert.False(t, browser.GetLockMessages())
+	browser.SetLockMessages(true)
+	assert.True(t, browser.GetLockMessages())
+
+	msgIterator, err := browser.GetEnumeration()
+	assert.Nil(t, err)
+
+	gotMsg1, gotErr := msgIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, gotMsg1)
+	assert.Equal(t, msg1.GetJMSMessageID(), gotMsg1.GetJMSMessageID())
+
+	// The locked message can't be seen by another browser.
+	otherBrowser, errBrowse := context.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if otherBrowser != nil {
+		defer otherBrowser.Close()
+	}
+
+	otherIterator, err := otherBrowser.GetEnumeration()
+	assert.Nil(t, err)
+	otherMsg, gotErr := otherIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, otherMsg)
+	assert.Equal(t, msg2.GetJMSMessageID(), otherMsg.GetJMSMessageID())
+
+	// Move on to the second message (which unlocks the first), and remove it.
+	gotMsg2, gotErr := msgIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, gotMsg2)
+	assert.Equal(t, msg2.GetJMSMessageID(), gotMsg2.GetJMSMessageID())
+
+	consumedMsg, errConsume := browser.ConsumeCurrent()
+	assert.Nil(t, errConsume)
+	assert.NotNil(t, consumedMsg)
+	assert.Equal(t, msg2.GetJMSMessageID(), consumedMsg.GetJMSMessageID())
+
+	switch msg := consumedMsg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, "browse consume msg 2", *msg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	// Browsing carries on from where the cursor was.
+	gotMsg3, gotErr := msgIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, gotMsg3)
+	assert.Equal(t, msg3.GetJMSMessageID(), gotMsg3.GetJMSMessageID())
+
+	gotMsg4, gotErr := msgIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.Nil(t, gotMsg4)
+
+	// Only the first and third messages are left on the queue.
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, msg1.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, msg3.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+}
+
+/*
+ * Test that the message under the browse cursor is consumed under syncpoint
+ * when the context is transacted.
+ */
+func TestBrowserConsumeCurrentTransacted(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	transactedContext, ctxErr := cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	assert.Nil(t, ctxErr)
+	if transactedContext != nil {
+		defer transactedContext.Close()
+	}
+
+	sendMsg := transactedContext.CreateTextMessageWithString("browse consume transacted")
+	queue := transactedContext.CreateQueue("DEV.QUEUE.1")
+	errSend := transactedContext.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+	transactedContext.Commit()
+
+	browser, errBrowse := transactedContext.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if browser != nil {
+		defer browser.Close()
+	}
+
+	msgIterator, err := browser.GetEnumeration()
+	assert.Nil(t, err)
+
+	gotMsg, gotErr := msgIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, gotMsg)
+	assert.Equal(t, sendMsg.GetJMSMessageID(), gotMsg.GetJMSMessageID())
+
+	consumedMsg, errConsume := browser.ConsumeCurrent()
+	assert.Nil(t, errConsume)
+	assert.NotNil(t, consumedMsg)
+
+	// Rolling back puts the message back onto the queue.
+	transactedContext.Rollback()
+
+	consumer, errCons := transactedContext.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, sendMsg.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+	transactedContext.Commit()
+
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test browsing only the messages that match a selector, for example all of
+ * the messages for one customer that have been sent with the same CorrelationID.
+ */
+func TestBrowserWithSelector(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Send messages for two different customers, interleaved on the queue.
+	customerA := "customer-A-0001"
+	customerB := "customer-B-0002"
+
+	msg1 := context.CreateTextMessageWithString("order 1 for A")
+	msg1.SetJMSCorrelationID(customerA)
+	msg2 := context.CreateTextMessageWithString("order 1 for B")
+	msg2.SetJMSCorrelationID(customerB)
+	msg3 := context.CreateTextMessageWithString("order 2 for A")
+	msg3.SetJMSCorrelationID(customerA)
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+	errSend := producer.Send(queue, msg1)
+	assert.Nil(t, errSend)
+	errSend = producer.Send(queue, msg2)
+	assert.Nil(t, errSend)
+	errSend = producer.Send(queue, msg3)
+	assert.Nil(t, errSend)
+
+	// Browse only the messages for the first customer.
+	selector := "JMSCorrelationID = '" + customerA + "'"
+	browser, errBrowse := context.CreateBrowserWithSelector(queue, selector)
+	assert.Nil(t, errBrowse)
+	if browser != nil {
+		defer browser.Close()
+	}
+
+	assert.Equal(t, selector, browser.GetMessageSelector())
+	assert.Equal(t, "DEV.QUEUE.1", browser.GetQueue().GetQueueName())
+
+	msgIterator, err := browser.GetEnumeration()
+	assert.Nil(t, err)
+
+	gotMsg1, gotErr1 := msgIterator.GetNext()
+	assert.Nil(t, gotErr1)
+	assert.NotNil(t, gotMsg1)
+	assert.Equal(t, msg1.GetJMSMessageID(), gotMsg1.GetJMSMessageID())
+
+	// The message for the other customer is skipped.
+	gotMsg3, gotErr3 := msgIterator.GetNext()
+	assert.Nil(t, gotErr3)
+	assert.NotNil(t, gotMsg3)
+	assert.Equal(t, msg3.GetJMSMessageID(), gotMsg3.GetJMSMessageID())
+
+	// No more messages left for this customer.
+	gotMsg4, gotErr4 := msgIterator.GetNext()
+	assert.Nil(t, gotErr4)
+	assert.Nil(t, gotMsg4)
+
+	// A browser without a selector has an empty selector string.
+	allBrowser, errBrowse := context.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if allBrowser != nil {
+		defer allBrowser.Close()
+	}
+	assert.Equal(t, "", allBrowser.GetMessageSelector())
+
+	// Invalid selectors are rejected when the browser is created.
+	badBrowser, errBrowse := context.CreateBrowserWithSelector(queue, "JMSPriority > 4")
+	assert.Nil(t, badBrowser)
+	assert.NotNil(t, errBrowse)
+	if errBrowse != nil {
+		assert.Equal(t, "MQJMS0004", errBrowse.GetErrorCode())
+	}
+
+	// Tidy up the messages by destructively consuming them with a
+	// real Consumer.
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	for _, msg := range []string{msg1.GetJMSMessageID(), msg2.GetJMSMessageID(), msg3.GetJMSMessageID()} {
+		gotMsg, gotErr := consumer.ReceiveNoWait()
+		assert.Nil(t, gotErr)
+		assert.NotNil(t, gotMsg)
+		if gotMsg != nil {
+			assert.Equal(t, msg, gotMsg.GetJMSMessageID())
+		}
+	}
+
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that text messages sent by other applications in EBCDIC and UTF-16 are
+ * converted into Go strings when they are received.
+ */
+func TestCharacterSetReceiveConversion(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	testCases := []struct {
+		ccsid    int
+		encoding int
+		body     []byte
+		expected string
+	}{
+		{37, int(ibmmq.MQENC_NATIVE), []byte{0xC8, 0x85, 0x93, 0x93, 0x96, 0x40, 0xBA, 0xF1, 0xBB, 0x5A}, "Hello [1]!"},
+		{500, int(ibmmq.MQENC_NATIVE), []byte{0xC8, 0x85, 0x93, 0x93, 0x96, 0x40, 0x4A, 0xF1, 0x5A, 0x4F}, "Hello [1]!"},
+		{1047, int(ibmmq.MQENC_NATIVE), []byte{0xC7, 0x99, 0xDC, 0x59, 0x85, 0x40, 0xAD, 0x5F, 0xBD}, "Grüße [^]"},
+		{1200, 273, []byte{0x00, 0x47, 0x00, 0x72, 0x00, 0xFC, 0x00, 0xDF, 0x00, 0x65}, "Grüße"},
+		{1200, 546, []byte{0x47, 0x00, 0x72, 0x00, 0xFC, 0x00, 0xDF, 0x00, 0x65, 0x00}, "Grüße"},
+		{1200, 273, []byte{0xFF, 0xFE, 0xAC, 0x20, 0x31, 0x00}, "€1"},
+		{819, int(ibmmq.MQENC_NATIVE), []byte{0x47, 0x72, 0xFC, 0xDF, 0x65}, "Grüße"},
+	}
+
+	for _, testCase := range testCases {
+
+		// Build the message in the way that another application would send it.
+		sendMsg := context.CreateBytesMessageWithBytes(testCase.body)
+		format := ibmmq.MQFMT_STRING
+		sendMsg.SetStringProperty("JMS_IBM_Format", &format)
+		sendMsg.SetIntProperty("JMS_IBM_Character_Set", testCase.ccsid)
+		sendMsg.SetIntProperty("JMS_IBM_Encoding", testCase.encoding)
+
+		errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+		assert.Nil(t, errSend)
+
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvMsg)
+
+		switch msg := rcvMsg.(type) {
+		case jms20subset.TextMessage:
+			assert.Equal(t, testCase.expected, *msg.GetText())
+		default:
+			assert.Fail(t, "Got something other than a text message")
+		}
+
+		gotCCSID, propErr := rcvMsg.GetIntProperty("JMS_IBM_Character_Set")
+		assert.Nil(t, propErr)
+		assert.Equal(t, testCase.ccsid, gotCCSID)
+	}
+
+}
+
+/*
+ * Test sending a text message in a specific character set, and having the
+ * queue manager convert it back to UTF-8 when it is received.
+ */
+func TestCharacterSetSendConversion(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	assert.Equal(t, jms20subset.Destination_RECEIVE_CONVERSION_CLIENT_MSG, queue.GetReceiveConversion())
+	assert.Equal(t, 1208, queue.GetReceiveCCSID())
+
+	msgText := "Grüße aus Köln [€]"
+
+	// The text is sent in EBCDIC, so the client converts it when it is received.
+	sendMsg := context.CreateTextMessageWithString(msgText)
+	sendMsg.SetIntProperty("JMS_IBM_Character_Set", 1047)
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// The euro sign is not in code page 1047, so it is replaced.
+	rcvText, errRcv := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Equal(t, "Grüße aus Köln [?]", *rcvText)
+
+	// UTF-16 can represent every character.
+	sendMsg = context.CreateTextMessageWithString(msgText)
+	sendMsg.SetIntProperty("JMS_IBM_Character_Set", 1200)
+	errSend = context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	// This time ask the queue manager to convert the message into UTF-8.
+	convertQueue := context.CreateQueue("DEV.QUEUE.1").SetReceiveConversion(jms20subset.Destination_RECEIVE_CONVERSION_QMGR)
+	convertConsumer, errCons := context.CreateConsumer(convertQueue)
+	assert.Nil(t, errCons)
+	if convertConsumer != nil {
+		defer convertConsumer.Close()
+	}
+
+	rcvMsg, errRcv := convertConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch msg := rcvMsg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, msgText, *msg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	gotCCSID, propErr := rcvMsg.GetIntProperty("JMS_IBM_Character_Set")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 1208, gotCCSID)
+
+	// Text that cannot be converted into the character set is rejected.
+	sendMsg = context.CreateTextMessageWithString(msgText)
+	sendMsg.SetIntProperty("JMS_IBM_Character_Set", 1051)
+	errSend = context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.NotNil(t, errSend)
+	if errSend != nil {
+		assert.Equal(t, "CharacterSetConversionFailed", errSend.GetErrorCode())
+	}
+
+	// A text message in a character set that cannot be converted is returned with
+	// its body as it was received, rather than being lost. This is "Hello" in
+	// EBCDIC code page 273 (Germany).
+	unconvertedBody := []byte{0xC8, 0x85, 0x93, 0x93, 0x96}
+	bytesMsg := context.CreateBytesMessageWithBytes(unconvertedBody)
+	format := "MQSTR"
+	bytesMsg.SetStringProperty("JMS_IBM_Format", &format)
+	bytesMsg.SetIntProperty("JMS_IBM_Character_Set", 273)
+	errSend = context.CreateProducer().SetTimeToLive(20000).Send(queue, bytesMsg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.NotNil(t, errRcv)
+	if errRcv != nil {
+		assert.Equal(t, "CharacterSetConversionFailed", errRcv.GetErrorCode())
+	}
+
+	switch msg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		assert.Equal(t, unconvertedBody, *msg.ReadBytes())
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+}
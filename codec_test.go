@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+// pipeCodec is an example of an application-provided codec that encodes a
+// slice of strings as a binary payload separated by pipe characters.
+type pipeCodec struct{}
+
+func (codec pipeCodec) GetName() string {
+	return "pipe"
+}
+
+func (codec pipeCodec) GetFormat() string {
+	return ""
+}
+
+func (codec pipeCodec) Marshal(v interface{}) ([]byte, error) {
+	values, ok := v.([]string)
+	if !ok {
+		return nil, errors.New("pipeCodec only supports []string")
+	}
+	return []byte(strings.Join(values, "|")), nil
+}
+
+func (codec pipeCodec) Unmarshal(data []byte, v interface{}) error {
+	values, ok := v.(*[]string)
+	if !ok {
+		return errors.New("pipeCodec only supports *[]string")
+	}
+	*values = strings.Split(string(data), "|")
+	return nil
+}
+
+/*
+ * Test creating messages from values using a codec, and decoding them again
+ * on receive using the codec that is recorded on the message.
+ */
+func TestCodec(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(10000)
+
+	// The JSON codec is available by default, and sends the body as a string.
+	sentOrder := order{ID: "B456", Quantity: 2, Price: 12.5}
+	msg, errMsg := context.CreateMessageFromValue(sentOrder, mqjms.JSONCodec{})
+	assert.Nil(t, errMsg)
+
+	codecName, propErr := msg.GetStringProperty(mqjms.MessageImpl_PROPERTY_CODEC)
+	assert.Nil(t, propErr)
+	assert.Equal(t, "json", *codecName)
+	format, propErr := msg.GetStringProperty("JMS_IBM_Format")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "MQSTR", *format)
+
+	errSend := producer.Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	gotOrder, errRcv := mqjms.ReceiveBodyNoWait[order](consumer)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, sentOrder, *gotOrder)
+
+	// A custom codec must be registered before messages it encodes can be decoded.
+	msg, errMsg = context.CreateMessageFromValue([]string{"a", "b", "c"}, pipeCodec{})
+	assert.Nil(t, errMsg)
+
+	// A binary codec doesn't set a format on the message.
+	format, propErr = msg.GetStringProperty("JMS_IBM_Format")
+	assert.Nil(t, propErr)
+	assert.Nil(t, format)
+
+	errSend = producer.Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	gotValues, errRcv := mqjms.ReceiveBodyNoWait[[]string](consumer)
+	assert.Nil(t, gotValues)
+	assert.NotNil(t, errRcv)
+	assert.IsType(t, jms20subset.MessageFormatException{}, errRcv)
+	assert.Equal(t, "UnknownCodec", errRcv.GetErrorCode())
+
+	mqjms.RegisterCodec(pipeCodec{})
+
+	// The message was backed out, so it can be received once the codec is registered.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	// The codec doesn't set a format so the message is received as a BytesMessage.
+	_, isBytes := rcvMsg.(jms20subset.BytesMessage)
+	assert.True(t, isBytes)
+
+	gotValues, errRcv = mqjms.GetBody[[]string](rcvMsg)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, []string{"a", "b", "c"}, *gotValues)
+
+	// Values that the codec can't encode are rejected.
+	_, errMsg = context.CreateMessageFromValue(42, pipeCodec{})
+	assert.NotNil(t, errMsg)
+	assert.IsType(t, jms20subset.MessageFormatException{}, errMsg)
+	assert.Equal(t, "BodyEncodeFailed", errMsg.GetErrorCode())
+
+}
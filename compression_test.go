@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that compressed message bodies are decompressed automatically when
+ * they are received, so that a large message fits within the default 32kb
+ * receive buffer.
+ */
+func TestCompression(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A repetitive XML payload over 32kb in length, which compresses well.
+	xmlBody := "<orders>" + strings.Repeat("<order><id>12345</id><status>SHIPPED</status></order>", 1000) + "</orders>"
+	assert.True(t, len(xmlBody) > 32768)
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(10000)
+	assert.Equal(t, jms20subset.Compression_NONE, producer.GetCompression())
+
+	producer = producer.SetCompression(jms20subset.Compression_GZIP)
+	assert.Equal(t, jms20subset.Compression_GZIP, producer.GetCompression())
+
+	// Send a TextMessage, which is left unchanged after it has been sent.
+	msg := context.CreateTextMessageWithString(xmlBody)
+	errSend := producer.Send(queue, msg)
+	assert.Nil(t, errSend)
+	assert.Equal(t, xmlBody, *msg.GetText())
+	propExists, propErr := msg.PropertyExists(mqjms.MessageImpl_PROPERTY_COMPRESSION)
+	assert.Nil(t, propErr)
+	assert.False(t, propExists)
+
+	// The compressed message fits in the default receive buffer, and is returned
+	// as a TextMessage with the original body.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch typedMsg := rcvMsg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, xmlBody, *typedMsg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	format, propErr := rcvMsg.GetStringProperty("JMS_IBM_Format")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "MQSTR", strings.TrimSpace(*format))
+
+	// The compression marker is removed from the received message.
+	propExists, propErr = rcvMsg.PropertyExists(mqjms.MessageImpl_PROPERTY_COMPRESSION)
+	assert.Nil(t, propErr)
+	assert.False(t, propExists)
+
+	// BytesMessages are compressed in the same way.
+	bytesBody := []byte(xmlBody)
+	errSend = producer.SendBytes(queue, bytesBody)
+	assert.Nil(t, errSend)
+
+	rcvBytes, errRcv := consumer.ReceiveBytesBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Equal(t, bytesBody, *rcvBytes)
+
+	// Empty bodies are sent without compression.
+	errSend = producer.SendString(queue, "")
+	assert.Nil(t, errSend)
+
+	rcvStr, errRcv := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvStr)
+
+	// Unsupported algorithms are rejected.
+	producer = producer.SetCompression("lzma")
+	assert.Equal(t, jms20subset.Compression_GZIP, producer.GetCompression())
+
+}
+
+/*
+ * Test that a message which would decompress to more than the configured maximum
+ * is returned as it was received, along with an error, rather than being lost.
+ */
+func TestCompressionLimit(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Limit the decompressed size of each message to 64kb.
+	cf.MaxDecompressedSize = 65536
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(10000).SetCompression(jms20subset.Compression_GZIP)
+
+	// A body within the limit is decompressed as normal.
+	smallBody := strings.Repeat("a", 65536)
+	errSend := producer.SendString(queue, smallBody)
+	assert.Nil(t, errSend)
+
+	rcvStr, errRcv := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvStr)
+	assert.Equal(t, smallBody, *rcvStr)
+
+	// Whereas one that is a single byte over the limit is not.
+	largeBody := strings.Repeat("a", 65537)
+	errSend = producer.SendString(queue, largeBody)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.NotNil(t, errRcv)
+	if errRcv != nil {
+		assert.Equal(t, "DecompressionFailed", errRcv.GetErrorCode())
+	}
+
+	// The message is returned with its compressed body and the compression marker.
+	assert.NotNil(t, rcvMsg)
+	switch typedMsg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		assert.True(t, typedMsg.GetBodyLength() < len(largeBody))
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+	algorithm, propErr := rcvMsg.GetStringProperty(mqjms.MessageImpl_PROPERTY_COMPRESSION)
+	assert.Nil(t, propErr)
+	assert.NotNil(t, algorithm)
+	if algorithm != nil {
+		assert.Equal(t, jms20subset.Compression_GZIP, *algorithm)
+	}
+
+}
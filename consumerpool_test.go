@@ -0,0 +1,339 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that a pool of consumers receives all of the messages on a queue and
+ * passes them to the handler.
+ */
+func TestConsumerPool(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Put some messages on the queue for the pool to process.
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+
+	numMsgs := 20
+	for i := 0; i < numMsgs; i++ {
+		errSend := producer.SendString(queue, "pool msg "+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+	}
+
+	// Record the messages that are passed to the handler.
+	var receivedLock sync.Mutex
+	received := make(map[string]bool)
+
+	handler := func(msg jms20subset.Message) error {
+		receivedLock.Lock()
+		defer receivedLock.Unlock()
+
+		received[*msg.(jms20subset.TextMessage).GetText()] = true
+		return nil
+	}
+
+	pool := mqjms.NewConsumerPool(cf, queue, 3, handler).SetReceiveWait(500)
+	errStart := pool.Start()
+	assert.Nil(t, errStart)
+	assert.Equal(t, 3, pool.GetConcurrency())
+
+	// Wait for the pool to receive all of the messages.
+	for i := 0; i < 50; i++ {
+		receivedLock.Lock()
+		count := len(received)
+		receivedLock.Unlock()
+
+		if count == numMsgs {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Scale the pool down, then stop it.
+	errScale := pool.ScaleTo(1)
+	assert.Nil(t, errScale)
+	assert.Equal(t, 1, pool.GetConcurrency())
+
+	pool.Stop()
+	assert.Equal(t, 0, pool.GetConcurrency())
+
+	assert.Equal(t, numMsgs, len(received))
+	for i := 0; i < numMsgs; i++ {
+		assert.True(t, received["pool msg "+strconv.Itoa(i)])
+	}
+
+}
+
+/*
+ * Test that a transacted pool of consumers rolls back the message if the
+ * handler returns an error, so that it is delivered again.
+ */
+func TestConsumerPoolTransacted(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	errSend := context.CreateProducer().SetTimeToLive(20000).SendString(queue, "transacted pool msg")
+	assert.Nil(t, errSend)
+
+	// Fail the first attempt to process the message.
+	var attemptsLock sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+
+	handler := func(msg jms20subset.Message) error {
+		attemptsLock.Lock()
+		defer attemptsLock.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			return errors.New("first attempt fails")
+		}
+
+		close(done)
+		return nil
+	}
+
+	var listenerErrs []jms20subset.JMSException
+	listener := func(err jms20subset.JMSException) {
+		listenerErrs = append(listenerErrs, err)
+	}
+
+	pool := mqjms.NewConsumerPool(cf, queue, 1, handler).
+		SetSessionMode(jms20subset.JMSContextSESSIONTRANSACTED).
+		SetReceiveWait(500).
+		SetExceptionListener(listener)
+
+	errStart := pool.Start()
+	assert.Nil(t, errStart)
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "Message was not redelivered")
+	}
+
+	pool.Stop()
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 1, len(listenerErrs))
+	assert.Equal(t, "MessageHandlerFailed", listenerErrs[0].GetErrorCode())
+
+	// The message was committed by the second attempt, so the queue is now empty.
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.Nil(t, rcvMsg)
+
+}
+
+/*
+ * Test that a transacted pool moves a message that keeps failing to the backout
+ * requeue queue once it reaches the backout threshold, including when the handler
+ * panics.
+ */
+func TestConsumerPoolBackout(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	attrs, errInq := context.InquireQueue(queue)
+	assert.Nil(t, errInq)
+	if attrs.BackoutThreshold == 0 || attrs.BackoutRequeueQName == "" {
+		t.Skip("Skipping test as DEV.QUEUE.1 does not have BOTHRESH and BOQNAME set")
+	}
+
+	sendMsg := context.CreateTextMessageWithString("poison pool msg")
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	var attemptsLock sync.Mutex
+	attempts := 0
+
+	handler := func(msg jms20subset.Message) error {
+		attemptsLock.Lock()
+		defer attemptsLock.Unlock()
+
+		attempts++
+		panic("handler cannot process the message")
+	}
+
+	pool := mqjms.NewConsumerPool(cf, queue, 1, handler).
+		SetSessionMode(jms20subset.JMSContextSESSIONTRANSACTED).
+		SetReceiveWait(500).
+		SetExceptionListener(func(err jms20subset.JMSException) {})
+
+	errStart := pool.Start()
+	assert.Nil(t, errStart)
+
+	// Wait for the message to arrive on the backout queue, with the same message ID.
+	backoutQueue := context.CreateQueue(attrs.BackoutRequeueQName)
+	backoutConsumer, errCons := context.CreateConsumerWithSelector(backoutQueue,
+		"JMSMessageID = '"+sendMsg.GetJMSMessageID()+"'")
+	assert.Nil(t, errCons)
+	if backoutConsumer != nil {
+		defer backoutConsumer.Close()
+	}
+
+	rcvMsg, errRcv := backoutConsumer.Receive(10000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	pool.Stop()
+
+	// The handler was called until the message reached the threshold.
+	assert.Equal(t, attrs.BackoutThreshold, attempts)
+
+}
+
+/*
+ * Test that a pool with auto-scaling adds consumers as messages build up on the
+ * queue, and removes them again once the queue is empty.
+ */
+func TestConsumerPoolAutoScaling(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+
+	numMsgs := 10
+	for i := 0; i < numMsgs; i++ {
+		errSend := producer.SendString(queue, "scaling msg "+strconv.Itoa(i))
+		assert.Nil(t, errSend)
+	}
+
+	// Hold on to each message until the pool has scaled up, so that they build up
+	// on the queue.
+	release := make(chan struct{})
+	handler := func(msg jms20subset.Message) error {
+		<-release
+		return nil
+	}
+
+	pool := mqjms.NewConsumerPool(cf, queue, 1, handler).
+		SetReceiveWait(500).
+		SetAutoScaling(1, 3, 2, 200*time.Millisecond)
+
+	errStart := pool.Start()
+	assert.Nil(t, errStart)
+	defer pool.Stop()
+
+	waitForConcurrency := func(expected int) {
+		for i := 0; i < 50 && pool.GetConcurrency() != expected; i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		assert.Equal(t, expected, pool.GetConcurrency())
+	}
+
+	// There are more messages than the maximum number of consumers can handle.
+	waitForConcurrency(3)
+
+	// Once the messages have been processed the pool scales back to the minimum.
+	close(release)
+	waitForConcurrency(1)
+
+}
+
+/*
+ * Test that a negative number of consumers is rejected.
+ */
+func TestConsumerPoolInvalidConcurrency(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	handler := func(msg jms20subset.Message) error {
+		return nil
+	}
+
+	pool := mqjms.NewConsumerPool(cf, queue, -1, handler)
+	errStart := pool.Start()
+	assert.NotNil(t, errStart)
+	if errStart != nil {
+		assert.Equal(t, "InvalidConcurrency", errStart.GetErrorCode())
+	}
+	assert.Equal(t, 0, pool.GetConcurrency())
+
+	errScale := pool.ScaleTo(-2)
+	assert.NotNil(t, errScale)
+	if errScale != nil {
+		assert.Equal(t, "InvalidConcurrency", errScale.GetErrorCode())
+	}
+
+}
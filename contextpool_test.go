@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that a pool of contexts can be shared between goroutines that are each
+ * sending messages in parallel.
+ */
+func TestContextPoolConcurrentSend(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Create a pool with a minimum of one context and a maximum of three, that closes
+	// idle contexts after a short period of time.
+	pool, poolErr := mqjms.CreateContextPool(cf, jms20subset.JMSContextAUTOACKNOWLEDGE, 1, 3, 2*time.Second)
+	assert.Nil(t, poolErr)
+	if pool != nil {
+		defer pool.Close()
+	}
+
+	numMsgs := 10
+	var wg sync.WaitGroup
+
+	// Send messages from several goroutines at the same time, each of which borrows
+	// its own context from the pool.
+	for i := 0; i < numMsgs; i++ {
+		wg.Add(1)
+
+		go func(msgNum int) {
+			defer wg.Done()
+
+			context, borrowErr := pool.Borrow(5000)
+			assert.Nil(t, borrowErr)
+			if context == nil {
+				return
+			}
+			defer pool.Return(context)
+
+			queue := context.CreateQueue("DEV.QUEUE.1")
+			errSend := context.CreateProducer().SetTimeToLive(20000).SendString(queue, "pooled msg "+strconv.Itoa(msgNum))
+			assert.Nil(t, errSend)
+
+		}(i)
+	}
+
+	wg.Wait()
+
+	// Tidy up the messages using a context from the pool.
+	context, borrowErr := pool.Borrow(5000)
+	assert.Nil(t, borrowErr)
+	assert.NotNil(t, context)
+	defer pool.Return(context)
+
+	consumer, errCons := context.CreateConsumer(context.CreateQueue("DEV.QUEUE.1"))
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	for i := 0; i < numMsgs; i++ {
+		rcvBody, errRvc := consumer.ReceiveStringBodyNoWait()
+		assert.Nil(t, errRvc)
+		assert.NotNil(t, rcvBody)
+	}
+
+	rcvBody, errRvc := consumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRvc)
+	assert.Nil(t, rcvBody)
+
+}
+
+/*
+ * Test that borrowing from a pool that has no more contexts available times
+ * out with an error.
+ */
+func TestContextPoolExhausted(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	pool, poolErr := mqjms.CreateContextPool(cf, jms20subset.JMSContextAUTOACKNOWLEDGE, 0, 1, 0)
+	assert.Nil(t, poolErr)
+	if pool != nil {
+		defer pool.Close()
+	}
+
+	context, borrowErr := pool.Borrow(0)
+	assert.Nil(t, borrowErr)
+	assert.NotNil(t, context)
+
+	// The only context is in use, so the second borrow times out.
+	context2, borrowErr2 := pool.Borrow(500)
+	assert.Nil(t, context2)
+	assert.NotNil(t, borrowErr2)
+	assert.Equal(t, "ContextPoolExhausted", borrowErr2.GetErrorCode())
+
+	// Once the context is returned it can be borrowed again.
+	pool.Return(context)
+
+	context3, borrowErr3 := pool.Borrow(500)
+	assert.Nil(t, borrowErr3)
+	assert.NotNil(t, context3)
+	pool.Return(context3)
+
+	// An invalid pool size is rejected.
+	_, poolErr = mqjms.CreateContextPool(cf, jms20subset.JMSContextAUTOACKNOWLEDGE, 2, 1, 0)
+	assert.NotNil(t, poolErr)
+	assert.Equal(t, "ContextPoolInvalidSize", poolErr.GetErrorCode())
+
+}
+
+/*
+ * Test that only contexts which are currently borrowed from the pool can be
+ * given back, and that closing the pool wakes up goroutines waiting to borrow.
+ */
+func TestContextPoolOwnership(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	pool, poolErr := mqjms.CreateContextPool(cf, jms20subset.JMSContextAUTOACKNOWLEDGE, 0, 1, 0)
+	assert.Nil(t, poolErr)
+	if pool != nil {
+		defer pool.Close()
+	}
+
+	context, borrowErr := pool.Borrow(0)
+	assert.Nil(t, borrowErr)
+	assert.NotNil(t, context)
+	pool.Return(context)
+
+	context2, borrowErr2 := pool.Borrow(500)
+	assert.Nil(t, borrowErr2)
+	assert.NotNil(t, context2)
+
+	// Returning the first context again doesn't free up the slot that is being
+	// used by the second borrower, even though both use the same connection.
+	pool.Return(context)
+
+	context3, borrowErr3 := pool.Borrow(500)
+	assert.Nil(t, context3)
+	assert.NotNil(t, borrowErr3)
+	assert.Equal(t, "ContextPoolExhausted", borrowErr3.GetErrorCode())
+
+	// Nor does discarding a context that didn't come from the pool.
+	otherContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if otherContext != nil {
+		defer otherContext.Close()
+	}
+	pool.Discard(otherContext)
+
+	context3, borrowErr3 = pool.Borrow(500)
+	assert.Nil(t, context3)
+	assert.NotNil(t, borrowErr3)
+
+	// A goroutine that is waiting for a context is woken up when the pool is closed.
+	waitErr := make(chan jms20subset.JMSException)
+	go func() {
+		_, err := pool.Borrow(0)
+		waitErr <- err
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	pool.Close()
+
+	select {
+	case err := <-waitErr:
+		assert.NotNil(t, err)
+		if err != nil {
+			assert.Equal(t, "ContextPoolClosed", err.GetErrorCode())
+		}
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Borrow was not woken up when the pool was closed")
+	}
+
+	// The borrowed context is closed when it is returned to the closed pool.
+	pool.Return(context2)
+
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that two dispatchers using cooperative browsers each claim different
+ * messages from the same queue, which workers can then receive by message ID.
+ */
+func TestCooperativeBrowse(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+
+	sentMsgs := []jms20subset.TextMessage{
+		context.CreateTextMessageWithString("dispatch msg 1"),
+		context.CreateTextMessageWithString("dispatch msg 2"),
+		context.CreateTextMessageWithString("dispatch msg 3"),
+		context.CreateTextMessageWithString("dispatch msg 4"),
+	}
+	for _, msg := range sentMsgs {
+		errSend := producer.Send(queue, msg)
+		assert.Nil(t, errSend)
+	}
+
+	// Each dispatcher has its own connection, in the same way as if they were
+	// running in separate processes.
+	dispatcherContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if dispatcherContext != nil {
+		defer dispatcherContext.Close()
+	}
+
+	dispatcher1, errBrowse := context.CreateCooperativeBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if dispatcher1 != nil {
+		defer dispatcher1.Close()
+	}
+
+	dispatcher2, errBrowse := dispatcherContext.CreateCooperativeBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if dispatcher2 != nil {
+		defer dispatcher2.Close()
+	}
+
+	iterator1, err := dispatcher1.GetEnumeration()
+	assert.Nil(t, err)
+	iterator2, err := dispatcher2.GetEnumeration()
+	assert.Nil(t, err)
+
+	// The dispatchers take turns to claim the next message, and never see a
+	// message that the other has already claimed.
+	claimedMsgIDs := []string{}
+	for i, msg := range sentMsgs {
+
+		iterator := iterator1
+		if i%2 == 1 {
+			iterator = iterator2
+		}
+
+		gotMsg, gotErr := iterator.GetNext()
+		assert.Nil(t, gotErr)
+		assert.NotNil(t, gotMsg)
+		if gotMsg != nil {
+			assert.Equal(t, msg.GetJMSMessageID(), gotMsg.GetJMSMessageID())
+			claimedMsgIDs = append(claimedMsgIDs, gotMsg.GetJMSMessageID())
+		}
+	}
+
+	// Every message has been claimed.
+	gotMsg, gotErr := iterator1.GetNext()
+	assert.Nil(t, gotErr)
+	assert.Nil(t, gotMsg)
+	gotMsg, gotErr = iterator2.GetNext()
+	assert.Nil(t, gotErr)
+	assert.Nil(t, gotMsg)
+
+	// A browser that isn't cooperating still sees all of the messages.
+	browser, errBrowse := context.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if browser != nil {
+		defer browser.Close()
+	}
+
+	browseIterator, err := browser.GetEnumeration()
+	assert.Nil(t, err)
+	gotMsg, gotErr = browseIterator.GetNext()
+	assert.Nil(t, gotErr)
+	assert.NotNil(t, gotMsg)
+	assert.Equal(t, sentMsgs[0].GetJMSMessageID(), gotMsg.GetJMSMessageID())
+
+	// The workers receive the messages that they were handed by message ID.
+	for _, msgID := range claimedMsgIDs {
+
+		worker, errCons := context.CreateConsumerWithSelector(queue, "JMSMessageID = '"+msgID+"'")
+		assert.Nil(t, errCons)
+
+		if worker != nil {
+			rcvMsg, errRcv := worker.ReceiveNoWait()
+			assert.Nil(t, errRcv)
+			assert.NotNil(t, rcvMsg)
+			if rcvMsg != nil {
+				assert.Equal(t, msgID, rcvMsg.GetJMSMessageID())
+			}
+
+			worker.Close()
+		}
+	}
+
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that the persistence, priority and expiry set on a queue override the
+ * settings of the producer.
+ */
+func TestDestinationProperties(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Check the default values, which defer to the producer.
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	assert.Equal(t, jms20subset.Destination_PERSISTENCE_APP, queue.GetPersistence())
+	assert.Equal(t, jms20subset.Destination_PRIORITY_APP, queue.GetPriority())
+	assert.Equal(t, jms20subset.Destination_EXPIRY_APP, queue.GetExpiry())
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().
+		SetDeliveryMode(jms20subset.DeliveryMode_NON_PERSISTENT).
+		SetPriority(3).
+		SetTimeToLive(20000)
+
+	// Explicit values on the queue take precedence over the producer.
+	overrideQueue := context.CreateQueue("DEV.QUEUE.1").
+		SetPersistence(jms20subset.Destination_PERSISTENCE_PERS).
+		SetPriority(7).
+		SetExpiry(60000)
+	assert.Equal(t, jms20subset.Destination_PERSISTENCE_PERS, overrideQueue.GetPersistence())
+	assert.Equal(t, 7, overrideQueue.GetPriority())
+	assert.Equal(t, 60000, overrideQueue.GetExpiry())
+
+	errSend := producer.SendString(overrideQueue, "OverrideMsg")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, jms20subset.DeliveryMode_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 7, rcvMsg.GetJMSPriority())
+	expiryMillis := rcvMsg.GetJMSExpiration() - rcvMsg.GetJMSTimestamp()
+	assert.True(t, expiryMillis > 55000 && expiryMillis <= 60000)
+
+	// Defer to the queue definition, which for the developer queues is
+	// non-persistent with priority zero.
+	qdefQueue := context.CreateQueue("DEV.QUEUE.1").
+		SetPersistence(jms20subset.Destination_PERSISTENCE_QDEF).
+		SetPriority(jms20subset.Destination_PRIORITY_QDEF).
+		SetExpiry(jms20subset.Destination_EXPIRY_UNLIMITED)
+
+	errSend = context.CreateProducer().
+		SetDeliveryMode(jms20subset.DeliveryMode_PERSISTENT).
+		SetTimeToLive(20000).
+		SendString(qdefQueue, "QDefMsg")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc = consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 0, rcvMsg.GetJMSPriority())
+	expiryMillis = rcvMsg.GetJMSExpiration() - rcvMsg.GetJMSTimestamp()
+	assert.True(t, expiryMillis <= 0)
+
+	// Invalid values are ignored.
+	invalidQueue := context.CreateQueue("DEV.QUEUE.1").
+		SetPersistence(5).
+		SetPriority(10).
+		SetExpiry(-5)
+	assert.Equal(t, jms20subset.Destination_PERSISTENCE_APP, invalidQueue.GetPersistence())
+	assert.Equal(t, jms20subset.Destination_PRIORITY_APP, invalidQueue.GetPriority())
+	assert.Equal(t, jms20subset.Destination_EXPIRY_APP, invalidQueue.GetExpiry())
+
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that the dead-letter header of a message is parsed, and the original
+ * message is returned with the details of the header as properties.
+ */
+func TestDeadLetterHeaderProperties(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// DEV.QUEUE.1 stands in for the dead-letter queue.
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	dlqMsg := createDeadLetterMessage(context, ibmmq.MQRC_Q_FULL, "DEV.QUEUE.2", "order 1001")
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, dlqMsg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	// The original text message is returned, without the header.
+	switch msg := rcvMsg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, "order 1001", *msg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	gotReason, propErr := rcvMsg.GetIntProperty("JMS_IBM_DLH_Reason")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int(ibmmq.MQRC_Q_FULL), gotReason)
+
+	gotDestQName, propErr := rcvMsg.GetStringProperty("JMS_IBM_DLH_DestQName")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "DEV.QUEUE.2", *gotDestQName)
+
+	gotDestQMgrName, propErr := rcvMsg.GetStringProperty("JMS_IBM_DLH_DestQMgrName")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "", *gotDestQMgrName)
+
+	gotPutApplName, propErr := rcvMsg.GetStringProperty("JMS_IBM_DLH_PutApplName")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "dlq_test", *gotPutApplName)
+
+	gotPutDate, propErr := rcvMsg.GetStringProperty("JMS_IBM_DLH_PutDate")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "20260101", *gotPutDate)
+
+	gotPutTime, propErr := rcvMsg.GetStringProperty("JMS_IBM_DLH_PutTime")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "12000000", *gotPutTime)
+
+	// A message that wasn't dead-lettered doesn't have the properties.
+	errSend = context.CreateProducer().SetTimeToLive(20000).SendString(queue, "not dead-lettered")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	gotDestQName, propErr = rcvMsg.GetStringProperty("JMS_IBM_DLH_DestQName")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotDestQName)
+
+}
+
+/*
+ * Test that the DLQHandler retries, forwards, discards or ignores messages
+ * according to its rules.
+ */
+func TestDLQHandlerRules(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	dlq := context.CreateQueue("DEV.QUEUE.1")
+	dlqConsumer, errCons := context.CreateConsumer(dlq)
+	assert.Nil(t, errCons)
+	if dlqConsumer != nil {
+		defer dlqConsumer.Close()
+	}
+
+	targetQueue := context.CreateQueue("DEV.QUEUE.2")
+	targetConsumer, errCons := context.CreateConsumer(targetQueue)
+	assert.Nil(t, errCons)
+	if targetConsumer != nil {
+		defer targetConsumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(20000)
+	retriedMsg := createDeadLetterMessage(context, ibmmq.MQRC_Q_FULL, "DEV.QUEUE.2", "retried")
+	errSend := producer.Send(dlq, retriedMsg)
+	assert.Nil(t, errSend)
+	forwardedMsg := createDeadLetterMessage(context, ibmmq.MQRC_UNKNOWN_OBJECT_NAME, "APP.ORDERS", "forwarded")
+	errSend = producer.Send(dlq, forwardedMsg)
+	assert.Nil(t, errSend)
+	errSend = producer.Send(dlq, createDeadLetterMessage(context, ibmmq.MQRC_NOT_AUTHORIZED, "APP.AUDIT", "discarded"))
+	assert.Nil(t, errSend)
+	errSend = producer.Send(dlq, createDeadLetterMessage(context, ibmmq.MQRC_MSG_TOO_BIG_FOR_Q, "DEV.QUEUE.2", "ignored"))
+	assert.Nil(t, errSend)
+
+	rules := []mqjms.DLQRule{
+		{Reason: ibmmq.MQRC_Q_FULL, Action: mqjms.DLQAction_RETRY, Retry: 3},
+		{Reason: ibmmq.MQRC_NOT_AUTHORIZED, Action: mqjms.DLQAction_DISCARD},
+		{DestQName: "APP.*", Action: mqjms.DLQAction_FORWARD, ForwardQName: "DEV.QUEUE.2"},
+		{Action: mqjms.DLQAction_IGNORE},
+	}
+
+	var listenerErrs []jms20subset.JMSException
+	handler := mqjms.NewDLQHandler(cf, dlq, rules).SetExceptionListener(func(err jms20subset.JMSException) {
+		listenerErrs = append(listenerErrs, err)
+	})
+
+	removed, errProcess := handler.ProcessMessages()
+	assert.Nil(t, errProcess)
+	assert.Equal(t, 3, removed)
+	assert.Equal(t, 0, len(listenerErrs))
+
+	// The retried and forwarded messages arrive on the target queue, keeping the
+	// message ID that they had on the dead-letter queue.
+	for i, sentMsg := range []jms20subset.BytesMessage{retriedMsg, forwardedMsg} {
+
+		movedMsg, errRcv := targetConsumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, movedMsg)
+
+		if movedMsg != nil {
+			assert.Equal(t, sentMsg.GetJMSMessageID(), movedMsg.GetJMSMessageID())
+
+			switch typedMsg := movedMsg.(type) {
+			case jms20subset.TextMessage:
+				assert.Equal(t, []string{"retried", "forwarded"}[i], *typedMsg.GetText())
+			default:
+				assert.Fail(t, "Got something other than a text message")
+			}
+		}
+	}
+
+	rcvMsg, errRcv := targetConsumer.ReceiveStringBodyNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+	// Only the ignored message is left on the dead-letter queue.
+	ignoredMsg, errRcv := dlqConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, ignoredMsg)
+
+	gotReason, propErr := ignoredMsg.GetIntProperty("JMS_IBM_DLH_Reason")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int(ibmmq.MQRC_MSG_TOO_BIG_FOR_Q), gotReason)
+
+	ignoredMsg, errRcv = dlqConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, ignoredMsg)
+
+}
+
+// createDeadLetterMessage builds a message in the form that the queue manager
+// puts on the dead-letter queue, with an MQDLH in front of the original body.
+func createDeadLetterMessage(context jms20subset.JMSContext, reason int32, destQName string, body string) jms20subset.BytesMessage {
+
+	dlh := ibmmq.NewMQDLH(nil)
+	dlh.Reason = reason
+	dlh.DestQName = destQName
+	dlh.Encoding = ibmmq.MQENC_NATIVE
+	dlh.CodedCharSetId = 1208
+	dlh.Format = ibmmq.MQFMT_STRING
+	dlh.PutApplType = ibmmq.MQAT_DEFAULT
+	dlh.PutApplName = "dlq_test"
+	dlh.PutDate = "20260101"
+	dlh.PutTime = "12000000"
+
+	msgBytes := append(dlh.Bytes(), []byte(body)...)
+
+	msg := context.CreateBytesMessageWithBytes(msgBytes)
+	format := ibmmq.MQFMT_DEAD_LETTER_HEADER
+	msg.SetStringProperty("JMS_IBM_Format", &format)
+
+	return msg
+}
@@ -10,6 +10,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"testing"
@@ -288,3 +289,70 @@ func TestCorrelIDParsingOnSend(t *testing.T) {
 	checkCorrelIDOnSendReceive(t, context, queue, producer, consumer, testCorrel, testCorrel)
 
 }
+
+/*
+ * Test setting and getting a binary correlation ID, for example one that was
+ * generated by a mainframe application, that is not valid as a text string.
+ */
+func TestGetByCorrelIDAsBytes(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A full 24 byte correlation ID that contains zero and non-printable bytes.
+	myCorrelID := []byte{0xC1, 0xC2, 0x00, 0xFF, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x00, 0x00}
+
+	msg := context.CreateTextMessageWithString("BinaryCorrelMsg")
+	assert.Nil(t, msg.GetJMSCorrelationIDAsBytes())
+
+	errCorrel := msg.SetJMSCorrelationIDAsBytes(myCorrelID)
+	assert.Nil(t, errCorrel)
+	assert.Equal(t, myCorrelID, msg.GetJMSCorrelationIDAsBytes())
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	// The message ID bytes are the same as the hex encoded string form.
+	msgIDBytes := msg.GetJMSMessageIDAsBytes()
+	assert.Equal(t, 24, len(msgIDBytes))
+	assert.Equal(t, msg.GetJMSMessageID(), hex.EncodeToString(msgIDBytes))
+
+	// Receive the message using the hex encoded form of the correlation ID.
+	correlIDConsumer, correlErr := context.CreateConsumerWithSelector(queue, "JMSCorrelationID = '"+hex.EncodeToString(myCorrelID)+"'")
+	assert.Nil(t, correlErr)
+	if correlIDConsumer != nil {
+		defer correlIDConsumer.Close()
+	}
+
+	rcvMsg, errRvc := correlIDConsumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, myCorrelID, rcvMsg.GetJMSCorrelationIDAsBytes())
+	assert.Equal(t, msgIDBytes, rcvMsg.GetJMSMessageIDAsBytes())
+
+	// Short values are padded with zeros.
+	shortMsg := context.CreateTextMessage()
+	errCorrel = shortMsg.SetJMSCorrelationIDAsBytes([]byte{0x01, 0x02, 0x03})
+	assert.Nil(t, errCorrel)
+	expected := make([]byte, 24)
+	copy(expected, []byte{0x01, 0x02, 0x03})
+	assert.Equal(t, expected, shortMsg.GetJMSCorrelationIDAsBytes())
+
+	// Values that are too long are rejected.
+	errCorrel = shortMsg.SetJMSCorrelationIDAsBytes(make([]byte, 25))
+	assert.NotNil(t, errCorrel)
+	assert.Equal(t, "InvalidCorrelationID", errCorrel.GetErrorCode())
+	assert.Equal(t, expected, shortMsg.GetJMSCorrelationIDAsBytes())
+
+}
@@ -1,6 +1,6 @@
 module github.com/ibm-messaging/mq-golang-jms20
 
-go 1.19
+go 1.23
 
 require (
 	github.com/ibm-messaging/mq-golang/v5 v5.6.0
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test the inquiry of the depth and attributes of a queue.
+ */
+func TestInquireQueue(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	// The test queue starts out empty.
+	attrs, inqErr := context.InquireQueue(queue)
+	assert.Nil(t, inqErr)
+	assert.Equal(t, 0, attrs.CurrentDepth)
+	assert.True(t, attrs.MaxDepth > 0)
+	assert.True(t, attrs.MaxMsgLength > 0)
+	assert.False(t, attrs.GetInhibited)
+	assert.False(t, attrs.PutInhibited)
+
+	// Put two messages and check that the depth is updated.
+	producer := context.CreateProducer().SetTimeToLive(20000)
+	errSend := producer.SendString(queue, "inquire msg 1")
+	assert.Nil(t, errSend)
+	errSend = producer.SendString(queue, "inquire msg 2")
+	assert.Nil(t, errSend)
+
+	attrs, inqErr = context.InquireQueue(queue)
+	assert.Nil(t, inqErr)
+	assert.Equal(t, 2, attrs.CurrentDepth)
+
+	// Opening a consumer increases the open input count.
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	attrs, inqErr = context.InquireQueue(queue)
+	assert.Nil(t, inqErr)
+	assert.Equal(t, 1, attrs.OpenInputCount)
+
+	// Tidy up the messages.
+	for i := 0; i < 2; i++ {
+		rcvMsg, errRvc := consumer.ReceiveNoWait()
+		assert.Nil(t, errRvc)
+		assert.NotNil(t, rcvMsg)
+	}
+
+	attrs, inqErr = context.InquireQueue(queue)
+	assert.Nil(t, inqErr)
+	assert.Equal(t, 0, attrs.CurrentDepth)
+
+	// Inquiring on a queue that doesn't exist returns an error.
+	_, inqErr = context.InquireQueue(context.CreateQueue("DOES.NOT.EXIST"))
+	assert.NotNil(t, inqErr)
+	assert.Equal(t, "2085", inqErr.GetErrorCode())
+	assert.Equal(t, "MQRC_UNKNOWN_OBJECT_NAME", inqErr.GetReason())
+
+}
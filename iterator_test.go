@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	gocontext "context"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test browsing the messages on a queue using a range loop, including breaking
+ * out of the loop early and browsing from a particular message onwards.
+ */
+func TestBrowserRangeIterator(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(20000)
+
+	sentMsgIDs := []string{}
+	for _, text := range []string{"range msg 1", "range msg 2", "range msg 3"} {
+		msg := context.CreateTextMessageWithString(text)
+		errSend := producer.Send(queue, msg)
+		assert.Nil(t, errSend)
+		sentMsgIDs = append(sentMsgIDs, msg.GetJMSMessageID())
+	}
+
+	browser, errBrowse := context.CreateBrowser(queue)
+	assert.Nil(t, errBrowse)
+	if browser != nil {
+		defer browser.Close()
+	}
+
+	// Browse all of the messages.
+	browsedMsgIDs := []string{}
+	for msg, err := range browser.All() {
+		assert.Nil(t, err)
+		browsedMsgIDs = append(browsedMsgIDs, msg.GetJMSMessageID())
+	}
+	assert.Equal(t, sentMsgIDs, browsedMsgIDs)
+
+	// Stop after the first message, with that message locked. Breaking out of
+	// the loop releases the lock.
+	browser.SetLockMessages(true)
+	for msg, err := range browser.All() {
+		assert.Nil(t, err)
+		assert.Equal(t, sentMsgIDs[0], msg.GetJMSMessageID())
+		break
+	}
+
+	// Iterating again starts from the first message, which is no longer locked.
+	browser.SetLockMessages(false)
+	browsedMsgIDs = []string{}
+	for msg, err := range browser.All() {
+		assert.Nil(t, err)
+		browsedMsgIDs = append(browsedMsgIDs, msg.GetJMSMessageID())
+	}
+	assert.Equal(t, sentMsgIDs, browsedMsgIDs)
+
+	// Browse from the second message onwards.
+	browsedMsgIDs = []string{}
+	for msg, err := range browser.AllFrom(sentMsgIDs[1]) {
+		assert.Nil(t, err)
+		browsedMsgIDs = append(browsedMsgIDs, msg.GetJMSMessageID())
+	}
+	assert.Equal(t, sentMsgIDs[1:], browsedMsgIDs)
+
+	// Tidy up the messages, using a range loop that ends once the queue is empty.
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	consumedMsgIDs := []string{}
+	for msg, err := range consumer.Messages(gocontext.Background(), 0) {
+		assert.Nil(t, err)
+		consumedMsgIDs = append(consumedMsgIDs, msg.GetJMSMessageID())
+	}
+	assert.Equal(t, sentMsgIDs, consumedMsgIDs)
+
+	// The message has now gone, so it can't be browsed from.
+	for msg, err := range browser.AllFrom(sentMsgIDs[1]) {
+		assert.Nil(t, msg)
+		assert.NotNil(t, err)
+		if jmsErr, ok := err.(jms20subset.JMSException); ok {
+			assert.Equal(t, "MessageNotFound", jmsErr.GetErrorCode())
+		}
+	}
+
+}
+
+/*
+ * Test receiving messages using a range loop that waits for messages to arrive
+ * until it is cancelled.
+ */
+func TestConsumerRangeIterator(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	msgText := "range consumer msg"
+	errSend := context.CreateProducer().SetTimeToLive(20000).SendString(queue, msgText)
+	assert.Nil(t, errSend)
+
+	goCtx, cancel := gocontext.WithCancel(gocontext.Background())
+	defer cancel()
+
+	// Cancelling the context finishes the loop before it waits for the next message.
+	count := 0
+	for msg, err := range consumer.Messages(goCtx, 500) {
+		assert.Nil(t, err)
+		assert.NotNil(t, msg)
+
+		switch msg := msg.(type) {
+		case jms20subset.TextMessage:
+			assert.Equal(t, msgText, *msg.GetText())
+		default:
+			assert.Fail(t, "Got something other than a text message")
+		}
+
+		count++
+		cancel()
+	}
+	assert.Equal(t, 1, count)
+
+	// A cancelled context returns no messages.
+	for range consumer.Messages(goCtx, 500) {
+		assert.Fail(t, "Received a message after the context was cancelled")
+	}
+
+}
@@ -0,0 +1,34 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// Codec converts between application values and the bytes that are carried in
+// the body of a message, for example using JSON, Protocol Buffers or Avro.
+//
+// This has no equivalent in Java JMS. The name of the codec is sent with each
+// message that it encodes so that the receiving application can select the
+// matching codec to decode it.
+type Codec interface {
+
+	// GetName returns the name that identifies the encoding, such as "json".
+	GetName() string
+
+	// GetFormat returns the MQ format name to set on messages that are encoded
+	// by this codec, for example "MQSTR" for a text encoding, or an empty
+	// string for a binary encoding.
+	GetFormat() string
+
+	// Marshal encodes the value into the bytes of a message body.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes the bytes of a message body into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
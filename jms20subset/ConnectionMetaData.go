@@ -0,0 +1,82 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// ConnectionMetaData provides information describing the JMS provider and the
+// queue manager that a JMSContext is connected to, as returned by
+// JMSContext.GetMetaData.
+//
+// In Java JMS this is an interface that only describes the provider, however
+// here it also contains a snapshot of the attributes of the queue manager.
+// Cluster membership is not included, as it can't be inquired from the queue
+// manager object.
+type ConnectionMetaData struct {
+
+	// JMSVersion is the version of the JMS API implemented by the provider.
+	JMSVersion string
+
+	// JMSMajorVersion is the major version number of the JMS API.
+	JMSMajorVersion int
+
+	// JMSMinorVersion is the minor version number of the JMS API.
+	JMSMinorVersion int
+
+	// JMSProviderName is the name of the JMS provider.
+	JMSProviderName string
+
+	// ProviderVersion is the module version of the provider, or "(devel)" if it
+	// is not being used as a versioned module.
+	ProviderVersion string
+
+	// ClientHeaderVersion is the version of the MQ client header files that the
+	// provider was compiled against, for example "9.4.0". The MQ client library
+	// that is loaded when the application runs can be a different version.
+	ClientHeaderVersion string
+
+	// QueueManagerName is the name of the connected queue manager.
+	QueueManagerName string
+
+	// QueueManagerIdentifier is the unique identifier of the connected queue manager.
+	QueueManagerIdentifier string
+
+	// CommandLevel is the command level of the queue manager, for example 940 for
+	// IBM MQ 9.4.0, which indicates the functions that the queue manager supports.
+	CommandLevel int
+
+	// Platform is the operating system on which the queue manager is running,
+	// for example ibmmq.MQPL_UNIX.
+	Platform int
+
+	// PlatformName is the name of the Platform, for example "MQPL_UNIX".
+	PlatformName string
+
+	// CodedCharSetId is the CCSID of the queue manager.
+	CodedCharSetId int
+}
+
+// ConnectionMetaData_CMDLEVEL_ASYNC_PUT is the command level from which queue
+// managers support asynchronous put (IBM MQ 7.0).
+const ConnectionMetaData_CMDLEVEL_ASYNC_PUT int = 700
+
+// ConnectionMetaData_CMDLEVEL_SHARED_SUBSCRIPTIONS is the command level from which
+// queue managers support JMS 2.0 shared subscriptions (IBM MQ 8.0).
+const ConnectionMetaData_CMDLEVEL_SHARED_SUBSCRIPTIONS int = 800
+
+// SupportsAsyncPut returns true if the queue manager supports asynchronous put.
+func (metaData ConnectionMetaData) SupportsAsyncPut() bool {
+	return metaData.CommandLevel >= ConnectionMetaData_CMDLEVEL_ASYNC_PUT
+}
+
+// SupportsSharedSubscriptions returns true if the queue manager supports JMS 2.0
+// shared subscriptions.
+func (metaData ConnectionMetaData) SupportsSharedSubscriptions() bool {
+	return metaData.CommandLevel >= ConnectionMetaData_CMDLEVEL_SHARED_SUBSCRIPTIONS
+}
@@ -55,3 +55,56 @@ const Destination_PUT_ASYNC_ALLOWED_DISABLED int = 0
 // Destination_PUT_ASYNC_ALLOWED_AS_DEST allows the async message behaviour to be controlled by
 // the queue on the queue manager.
 const Destination_PUT_ASYNC_ALLOWED_AS_DEST int = -1
+
+// The Destination_PERSISTENCE_* values are the same as those used by the IBM MQ
+// classes for JMS, so that they can be exchanged in queue URIs.
+
+// Destination_PERSISTENCE_APP indicates that messages sent to the destination use
+// the delivery mode of the producer (default).
+const Destination_PERSISTENCE_APP int = -1
+
+// Destination_PERSISTENCE_QDEF indicates that messages sent to the destination use
+// the default persistence (DEFPSIST) configured on the queue.
+const Destination_PERSISTENCE_QDEF int = 0
+
+// Destination_PERSISTENCE_NON indicates that messages sent to the destination are
+// always non-persistent, regardless of the delivery mode of the producer.
+const Destination_PERSISTENCE_NON int = 1
+
+// Destination_PERSISTENCE_PERS indicates that messages sent to the destination are
+// always persistent, regardless of the delivery mode of the producer.
+const Destination_PERSISTENCE_PERS int = 2
+
+// Destination_PERSISTENCE_HIGH indicates that messages sent to the destination are
+// always non-persistent, which the IBM MQ classes for JMS use alongside the high
+// non-persistent message class (NPMCLASS) of the queue.
+const Destination_PERSISTENCE_HIGH int = 3
+
+// Destination_PRIORITY_APP indicates that messages sent to the destination use
+// the priority of the producer (default).
+const Destination_PRIORITY_APP int = -1
+
+// Destination_PRIORITY_QDEF indicates that messages sent to the destination use
+// the default priority (DEFPRTY) configured on the queue.
+const Destination_PRIORITY_QDEF int = -2
+
+// Destination_EXPIRY_APP indicates that messages sent to the destination use
+// the time to live of the producer (default).
+const Destination_EXPIRY_APP int = -2
+
+// Destination_EXPIRY_UNLIMITED indicates that messages sent to the destination
+// never expire, regardless of the time to live of the producer.
+const Destination_EXPIRY_UNLIMITED int = 0
+
+// Destination_RECEIVE_CONVERSION_CLIENT_MSG indicates that text messages received
+// from the destination are converted by the client from the CCSID of each message
+// (default).
+const Destination_RECEIVE_CONVERSION_CLIENT_MSG int = 1
+
+// Destination_RECEIVE_CONVERSION_QMGR indicates that the queue manager converts
+// messages received from the destination into the receive CCSID.
+const Destination_RECEIVE_CONVERSION_QMGR int = 2
+
+// Destination_RECEIVE_CCSID_DEFAULT is the CCSID (UTF-8) that the queue manager
+// converts messages into, unless another is specified.
+const Destination_RECEIVE_CCSID_DEFAULT int = 1208
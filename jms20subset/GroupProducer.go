@@ -0,0 +1,38 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// GroupProducer provides the ability for an application to send a sequence of
+// messages to a Destination as a message group, for which the provider assigns
+// the JMSXGroupID and JMSXGroupSeq properties.
+//
+// Once the last message in a group has been sent, the next message that is sent
+// starts a new group.
+type GroupProducer interface {
+
+	// Send adds a message to the current group, starting a new group if
+	// there isn't one in progress.
+	Send(msg Message) JMSException
+
+	// SendLast adds the final message to the current group, which sets the
+	// JMS_IBM_Last_Msg_In_Group property and completes the group.
+	SendLast(msg Message) JMSException
+
+	// GetGroupID returns the JMSXGroupID of the current group, or of the
+	// most recently completed group, or an empty string if no messages
+	// have been sent.
+	GetGroupID() string
+
+	// Closes the GroupProducer in order to free up any resources that were
+	// allocated by the provider. Closing a GroupProducer while a group is in
+	// progress leaves the group incomplete.
+	Close()
+}
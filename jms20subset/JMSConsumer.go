@@ -10,6 +10,12 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import (
+	"context"
+	"io"
+	"iter"
+)
+
 // JMSConsumer provides the ability for an application to receive messages
 // from a queue or a topic.
 //
@@ -51,6 +57,27 @@ type JMSConsumer interface {
 	// indefinitely.
 	ReceiveBytesBody(waitMillis int32) (*[]byte, JMSException)
 
+	// ReceiveStream returns a reader for the body of the next message, which may
+	// have been split into segments by JMSProducer.SendStream so that the whole of
+	// the body never needs to be held in memory. If a message is not immediately
+	// available the method will block for up to the specified number of
+	// milliseconds to wait for one to become available. A value of zero or less
+	// indicates to wait indefinitely. If no message arrives then nil is returned.
+	//
+	// The reader must be closed once it is no longer required. Streams are not
+	// compressed or protected.
+	ReceiveStream(waitMillis int32) (io.ReadCloser, JMSException)
+
+	// Messages returns a sequence of the messages received by this JMSConsumer,
+	// for use in a "for msg, err := range consumer.Messages(...)" loop. Each
+	// receive waits for up to the specified number of milliseconds for a message
+	// to arrive, and the sequence continues until the ctx is done or the loop is
+	// exited. A value of zero or less indicates not to wait, so the sequence ends
+	// as soon as there is no message immediately available.
+	//
+	// If an error occurs it is returned as the final element of the sequence.
+	Messages(ctx context.Context, waitMillis int32) iter.Seq2[Message, error]
+
 	// Closes the JMSConsumer in order to free up any resources that were
 	// allocated by the provider on behalf of this consumer.
 	Close()
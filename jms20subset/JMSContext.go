@@ -41,10 +41,25 @@ type JMSContext interface {
 	// name and different parameters we must use a different function name.
 	CreateConsumerWithSelector(dest Destination, selector string) (JMSConsumer, JMSException)
 
+	// CreateGroupConsumer creates a consumer for the specified Destination that
+	// only receives complete message groups, with the messages in each group
+	// received in the order that they were sent.
+	CreateGroupConsumer(dest Destination) (JMSConsumer, JMSException)
+
 	// CreateBrowser creates a consumer for the specified Destination so that
 	// an application can look at messages without removing them.
 	CreateBrowser(dest Destination) (QueueBrowser, JMSException)
 
+	// CreateBrowserWithSelector creates a consumer for the specified Destination
+	// using a message selector, so that an application can look at the messages
+	// that match the selector criteria without removing them.
+	CreateBrowserWithSelector(dest Destination, selector string) (QueueBrowser, JMSException)
+
+	// CreateCooperativeBrowser creates a browser for the specified Destination
+	// that cooperates with the other cooperative browsers of the queue, so that
+	// each message is only returned by one of them until it is received.
+	CreateCooperativeBrowser(dest Destination) (QueueBrowser, JMSException)
+
 	// CreateQueue creates a queue object which encapsulates a provider specific
 	// queue name.
 	//
@@ -53,6 +68,18 @@ type JMSContext interface {
 	// performed by an administrator using provider-specific tooling.
 	CreateQueue(queueName string) Queue
 
+	// InquireQueue returns the current depth and a selection of the configuration
+	// attributes of the specified queue.
+	//
+	// This has no equivalent in Java JMS, but is useful for applications such as
+	// autoscalers and health checks that need to monitor the state of a queue.
+	InquireQueue(dest Destination) (QueueAttributes, JMSException)
+
+	// GetMetaData returns information about the JMS provider, and the name,
+	// command level, platform and other attributes of the queue manager that
+	// this context is connected to.
+	GetMetaData() (ConnectionMetaData, JMSException)
+
 	// CreateTextMessage creates a message object that is used to send a string
 	// from one application to another.
 	CreateTextMessage() TextMessage
@@ -72,6 +99,11 @@ type JMSContext interface {
 	// of bytes from one application to another.
 	CreateBytesMessageWithBytes(bytes []byte) BytesMessage
 
+	// CreateMessageFromValue creates a message whose body contains the value
+	// encoded using the specified Codec, and records the name of the codec on
+	// the message so that the receiving application can decode it.
+	CreateMessageFromValue(v interface{}, codec Codec) (BytesMessage, JMSException)
+
 	// Commit confirms all messages sent/received during this transaction.
 	Commit() JMSException
 
@@ -10,6 +10,8 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import "io"
+
 // JMSProducer is a simple object used to send messages on behalf of a
 // JMSContext. It provides various methods to send a message to a specified
 // Destination. It also provides methods to allow message options to be
@@ -34,6 +36,17 @@ type JMSProducer interface {
 	// name and different parameters we must use a different function name.
 	SendBytes(dest Destination, body []byte) JMSException
 
+	// SendStream sends the content of the reader to the specified Destination
+	// as a single message, which may be split into segments so that the whole
+	// of the content never needs to be held in memory. Streams are not
+	// compressed or protected.
+	SendStream(dest Destination, reader io.Reader) JMSException
+
+	// CreateGroupProducer creates a GroupProducer that sends messages to the
+	// specified Destination as message groups, using the message options that
+	// are defined on this JMSProducer.
+	CreateGroupProducer(dest Destination) (GroupProducer, JMSException)
+
 	// SetDeliveryMode sets the delivery mode of messages sent using this
 	// JMSProducer - for example whether a message is persistent or non-persistent.
 	//
@@ -66,4 +79,15 @@ type JMSProducer interface {
 	// GetPriority returns the priority for all messages sent by this producer.
 	// Default priority is 4.
 	GetPriority() int
+
+	// SetCompression sets the algorithm used to compress the body of messages
+	// sent by this producer, which the consumer reverses automatically.
+	//
+	// Permitted arguments to this method are jms20subset.Compression_NONE (default)
+	// and jms20subset.Compression_GZIP.
+	SetCompression(algorithm string) JMSProducer
+
+	// GetCompression returns the algorithm used to compress the body of messages
+	// sent by this producer.
+	GetCompression() string
 }
@@ -37,6 +37,20 @@ type Message interface {
 	// GetJMSCorrelationID returns the correlation ID of this message.
 	GetJMSCorrelationID() string
 
+	// SetJMSCorrelationIDAsBytes sets the correlation ID for the message as an
+	// array of bytes, which is stored exactly as provided in the 24 byte MQ
+	// correlation ID field. Values shorter than 24 bytes are padded with zeros
+	// and values longer than 24 bytes are rejected with an error.
+	SetJMSCorrelationIDAsBytes(correlID []byte) JMSException
+
+	// GetJMSCorrelationIDAsBytes returns the 24 byte correlation ID of this message
+	// exactly as it is stored in the MQ correlation ID field.
+	GetJMSCorrelationIDAsBytes() []byte
+
+	// GetJMSMessageIDAsBytes returns the 24 byte ID of this message exactly as
+	// it is stored in the MQ message ID field.
+	GetJMSMessageIDAsBytes() []byte
+
 	// SetJMSReplyTo sets the Destination to which a reply to this message should
 	// be sent. If it is nil then no reply is expected.
 	SetJMSReplyTo(dest Destination) JMSException
@@ -55,6 +69,22 @@ type Message interface {
 	// GetJMSPriority returns the priority that is specified for this message.
 	GetJMSPriority() int
 
+	// SetJMSType sets the message type, which applications can use to identify
+	// the structure of the message body. An empty string removes the type.
+	SetJMSType(jmsType string) JMSException
+
+	// GetJMSType returns the message type, or an empty string if it is not set.
+	GetJMSType() string
+
+	// GetJMSDestination returns the Destination to which the message was sent.
+	// Returns nil if the message has not been sent or received.
+	GetJMSDestination() Destination
+
+	// GetJMSDeliveryTime returns the earliest time at which the message can be
+	// delivered to a consumer. Delivery delay is not currently supported, so this
+	// is the same as the timestamp at which the message was sent.
+	GetJMSDeliveryTime() int64
+
 	// SetStringProperty enables an application to set a string-type message property.
 	//
 	// value is *string which allows a nil value to be specified, to unset an individual
@@ -86,6 +116,56 @@ type Message interface {
 	// Returns false if the named property is not set.
 	GetBooleanProperty(name string) (bool, JMSException)
 
+	// SetByteProperty enables an application to set a byte-type (int8) message property.
+	SetByteProperty(name string, value int8) JMSException
+
+	// GetByteProperty returns the byte (int8) value of a named message property.
+	// Returns 0 if the named property is not set.
+	GetByteProperty(name string) (int8, JMSException)
+
+	// SetShortProperty enables an application to set a short-type (int16) message property.
+	SetShortProperty(name string, value int16) JMSException
+
+	// GetShortProperty returns the short (int16) value of a named message property.
+	// Returns 0 if the named property is not set.
+	GetShortProperty(name string) (int16, JMSException)
+
+	// SetLongProperty enables an application to set a long-type (int64) message property.
+	SetLongProperty(name string, value int64) JMSException
+
+	// GetLongProperty returns the long (int64) value of a named message property.
+	// Returns 0 if the named property is not set.
+	GetLongProperty(name string) (int64, JMSException)
+
+	// SetFloatProperty enables an application to set a float-type (float32) message property.
+	SetFloatProperty(name string, value float32) JMSException
+
+	// GetFloatProperty returns the float (float32) value of a named message property.
+	// Returns 0 if the named property is not set.
+	GetFloatProperty(name string) (float32, JMSException)
+
+	// SetBytesProperty enables an application to set a byte array message property.
+	//
+	// A nil value can be specified to unset an individual property.
+	SetBytesProperty(name string, value []byte) JMSException
+
+	// GetBytesProperty returns the byte array value of a named message property.
+	// Returns nil if the named property is not set.
+	GetBytesProperty(name string) ([]byte, JMSException)
+
+	// SetObjectProperty enables an application to set a message property using
+	// any of the supported types; bool, int8, int16, int32, int, int64, float32,
+	// float64, string and []byte.
+	//
+	// A nil value can be specified to unset an individual property.
+	SetObjectProperty(name string, value interface{}) JMSException
+
+	// GetObjectProperty returns the value of a named message property in the
+	// type with which it was set, for example int64 for a long property sent
+	// by a Java application.
+	// Returns nil if the named property is not set.
+	GetObjectProperty(name string) (interface{}, JMSException)
+
 	// PropertyExists returns true if the named message property exists on this message.
 	PropertyExists(name string) (bool, JMSException)
 
@@ -96,4 +176,10 @@ type Message interface {
 
 	// ClearProperties removes all message properties from this message.
 	ClearProperties() JMSException
+
+	// Close releases the resources that were allocated by the provider for this
+	// message, such as the storage for its properties. The message must not be
+	// used after it has been closed. Messages that are not closed have their
+	// resources released when they are garbage collected.
+	Close()
 }
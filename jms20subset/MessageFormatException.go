@@ -0,0 +1,35 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// MessageFormatException is returned when an application attempts to read the
+// body of a message as a type that does not match the kind of message, or
+// when the body cannot be decoded into the requested type.
+//
+// Applications can distinguish it from other errors using a type assertion, as
+// it implements the JMSException interface.
+type MessageFormatException struct {
+	JMSExceptionImpl
+}
+
+// CreateMessageFormatException is a helper function for creating a MessageFormatException
+func CreateMessageFormatException(reason string, errorCode string, linkedErr error) JMSException {
+
+	ex := MessageFormatException{
+		JMSExceptionImpl: JMSExceptionImpl{
+			reason:    reason,
+			errorCode: errorCode,
+			linkedErr: linkedErr,
+		},
+	}
+
+	return ex
+}
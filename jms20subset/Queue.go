@@ -22,4 +22,75 @@ type Queue interface {
 	// GetQueueName returns the provider-specific name of the queue that is
 	// represented by this object.
 	GetQueueName() string
+
+	// SetQueueManagerName sets the name of the queue manager on which the queue
+	// is hosted, so that messages can be sent to a queue on a remote queue manager,
+	// for example when sending a reply across a cluster of queue managers.
+	//
+	// An empty string (the default) indicates the queue manager to which the
+	// application is connected.
+	SetQueueManagerName(qMgrName string) Queue
+
+	// GetQueueManagerName returns the name of the queue manager on which the
+	// queue is hosted.
+	GetQueueManagerName() string
+
+	// SetPersistence controls the persistence of messages sent to this queue,
+	// overriding the delivery mode of the producer.
+	//
+	// Permitted values are:
+	//  * Destination_PERSISTENCE_APP - use the delivery mode of the producer (default)
+	//  * Destination_PERSISTENCE_QDEF - use the default persistence of the queue
+	//  * Destination_PERSISTENCE_PERS - messages are always persistent
+	//  * Destination_PERSISTENCE_NON - messages are always non-persistent
+	//  * Destination_PERSISTENCE_HIGH - messages are always non-persistent
+	SetPersistence(persistence int) Queue
+
+	// GetPersistence returns the persistence setting for this queue.
+	GetPersistence() int
+
+	// SetPriority controls the priority of messages sent to this queue, overriding
+	// the priority of the producer.
+	//
+	// Permitted values are:
+	//  * Destination_PRIORITY_APP - use the priority of the producer (default)
+	//  * Destination_PRIORITY_QDEF - use the default priority of the queue
+	//  * 0 to 9 - messages are sent with the specified priority
+	SetPriority(priority int) Queue
+
+	// GetPriority returns the priority setting for this queue.
+	GetPriority() int
+
+	// SetExpiry controls the time to live of messages sent to this queue,
+	// overriding the time to live of the producer.
+	//
+	// Permitted values are:
+	//  * Destination_EXPIRY_APP - use the time to live of the producer (default)
+	//  * Destination_EXPIRY_UNLIMITED - messages never expire
+	//  * a positive number of milliseconds after which messages expire
+	SetExpiry(expiry int) Queue
+
+	// GetExpiry returns the expiry setting for this queue.
+	GetExpiry() int
+
+	// SetReceiveConversion controls whether the queue manager converts the
+	// messages received from this queue into the receive CCSID.
+	//
+	// Permitted values are:
+	//  * Destination_RECEIVE_CONVERSION_CLIENT_MSG - text messages are converted
+	//    by the client from the CCSID of the message (default)
+	//  * Destination_RECEIVE_CONVERSION_QMGR - the queue manager converts messages
+	//    into the receive CCSID, for example using a data conversion exit
+	SetReceiveConversion(conversion int) Queue
+
+	// GetReceiveConversion returns the receive conversion setting for this queue.
+	GetReceiveConversion() int
+
+	// SetReceiveCCSID sets the CCSID that the queue manager converts messages into
+	// when the receive conversion is Destination_RECEIVE_CONVERSION_QMGR. The
+	// default is 1208 (UTF-8).
+	SetReceiveCCSID(ccsid int) Queue
+
+	// GetReceiveCCSID returns the receive CCSID for this queue.
+	GetReceiveCCSID() int
 }
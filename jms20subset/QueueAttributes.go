@@ -0,0 +1,54 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// QueueAttributes contains a snapshot of the current state and configuration
+// of a queue, as returned by JMSContext.InquireQueue.
+//
+// There is no equivalent in Java JMS, where this information is normally obtained
+// using provider-specific administration tools.
+type QueueAttributes struct {
+
+	// CurrentDepth is the number of messages currently on the queue.
+	CurrentDepth int
+
+	// MaxDepth is the maximum number of messages allowed on the queue.
+	MaxDepth int
+
+	// OpenInputCount is the number of handles that are currently open to get
+	// messages from the queue.
+	OpenInputCount int
+
+	// OpenOutputCount is the number of handles that are currently open to put
+	// messages to the queue.
+	OpenOutputCount int
+
+	// BackoutThreshold is the number of times a message can be backed out before
+	// it is moved to the BackoutRequeueQName (BOTHRESH).
+	BackoutThreshold int
+
+	// BackoutRequeueQName is the name of the queue to which messages are moved
+	// when they exceed the BackoutThreshold (BOQNAME).
+	BackoutRequeueQName string
+
+	// MaxMsgLength is the maximum length of a message on the queue (MAXMSGL).
+	MaxMsgLength int
+
+	// PropertyControl defines how message properties are handled for applications
+	// that get messages from the queue (PROPCTL), for example ibmmq.MQPROP_COMPATIBILITY.
+	PropertyControl int
+
+	// GetInhibited is true if get operations are not currently allowed on the queue.
+	GetInhibited bool
+
+	// PutInhibited is true if put operations are not currently allowed on the queue.
+	PutInhibited bool
+}
@@ -10,6 +10,8 @@
 // Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
 package jms20subset
 
+import "iter"
+
 // QueueBrowser provides the ability for an application to look at messages on
 // a queue without removing them.
 type QueueBrowser interface {
@@ -18,6 +20,41 @@ type QueueBrowser interface {
 	// queue messages in the order they would be received.
 	GetEnumeration() (MessageIterator, JMSException)
 
+	// All returns a sequence of the messages on the queue, starting from the
+	// first message each time it is iterated, for use in a
+	// "for msg, err := range browser.All()" loop.
+	//
+	// If an error occurs it is returned as the final element of the sequence.
+	All() iter.Seq2[Message, error]
+
+	// AllFrom returns a sequence of the messages on the queue, starting from
+	// the message with the specified JMSMessageID.
+	//
+	// If an error occurs it is returned as the final element of the sequence.
+	AllFrom(msgID string) iter.Seq2[Message, error]
+
+	// GetQueue returns the queue associated with this QueueBrowser.
+	GetQueue() Queue
+
+	// GetMessageSelector returns the message selector for this QueueBrowser,
+	// or an empty string if no selector was specified.
+	GetMessageSelector() string
+
+	// ConsumeCurrent destructively receives the message that was most recently
+	// returned by the browse enumeration, for example once an application has
+	// inspected it and decided that it should be removed from the queue.
+	//
+	// The message is received under syncpoint if the JMSContext is transacted.
+	ConsumeCurrent() (Message, JMSException)
+
+	// SetLockMessages controls whether each message that is browsed is locked
+	// until the next message is browsed, so that no other application can
+	// browse or receive it while it is being inspected.
+	SetLockMessages(lock bool) QueueBrowser
+
+	// GetLockMessages returns whether messages are locked as they are browsed.
+	GetLockMessages() bool
+
 	// Closes the QueueBrowser in order to free up any resources that were
 	// allocated by the provider.
 	Close()
@@ -0,0 +1,52 @@
+// Derived from the Eclipse Project for JMS, available at;
+//     https://github.com/eclipse-ee4j/jms-api
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package jms20subset provides interfaces for messaging applications in the style of the Java Message Service (JMS) API.
+package jms20subset
+
+// ReportType_COA identifies a confirm-on-arrival report.
+const ReportType_COA string = "COA"
+
+// ReportType_COD identifies a confirm-on-delivery report.
+const ReportType_COD string = "COD"
+
+// ReportType_EXPIRATION identifies a report that the original message expired.
+const ReportType_EXPIRATION string = "EXPIRATION"
+
+// ReportType_PAN identifies a positive action notification.
+const ReportType_PAN string = "PAN"
+
+// ReportType_NAN identifies a negative action notification.
+const ReportType_NAN string = "NAN"
+
+// ReportType_EXCEPTION identifies a report that the original message could not
+// be delivered, where the feedback code gives the reason.
+const ReportType_EXCEPTION string = "EXCEPTION"
+
+// ReportMessage is a message that was generated by the messaging provider or by
+// an application to report on the progress of an earlier message, for example
+// that it arrived on its destination queue.
+//
+// The JMSCorrelationID of a report normally matches the JMSMessageID of the
+// original message, and the body contains any data from the original message
+// that was requested when the report was requested.
+type ReportMessage interface {
+	BytesMessage
+
+	// GetReportType returns the kind of report, for example ReportType_COA.
+	GetReportType() string
+
+	// GetFeedback returns the feedback code of the report.
+	GetFeedback() int
+
+	// GetFeedbackName returns a description of the feedback code of the report,
+	// for example "MQFB_COA", or the name of the reason code for an exception
+	// report such as "MQRC_Q_FULL".
+	GetFeedbackName() string
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test the JMSType, JMSDestination and JMSDeliveryTime header fields.
+ */
+func TestJMSHeaders(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	msg := context.CreateTextMessageWithString("HeadersMsg")
+
+	// Check the values before they are set.
+	assert.Equal(t, "", msg.GetJMSType())
+	assert.Nil(t, msg.GetJMSDestination())
+	assert.Equal(t, int64(0), msg.GetJMSDeliveryTime())
+
+	// Clearing a type that isn't set is not an error.
+	assert.Nil(t, msg.SetJMSType(""))
+
+	msgType := "OrderCreated"
+	assert.Nil(t, msg.SetJMSType(msgType))
+	assert.Equal(t, msgType, msg.GetJMSType())
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	errSend := context.CreateProducer().SetTimeToLive(10000).Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	// The destination is set on the message when it is sent.
+	assert.NotNil(t, msg.GetJMSDestination())
+	assert.Equal(t, "DEV.QUEUE.1", msg.GetJMSDestination().GetDestinationName())
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	assert.Equal(t, msgType, rcvMsg.GetJMSType())
+	assert.NotNil(t, rcvMsg.GetJMSDestination())
+	assert.Equal(t, "DEV.QUEUE.1", rcvMsg.GetJMSDestination().GetDestinationName())
+
+	// The destination is carried with the message in the same way as the IBM MQ
+	// classes for JMS.
+	dstURI, propErr := rcvMsg.GetStringProperty("jms.Dst")
+	assert.Nil(t, propErr)
+	assert.NotNil(t, dstURI)
+	if dstURI != nil {
+		assert.Equal(t, "queue:///DEV.QUEUE.1", *dstURI)
+	}
+
+	// Delivery delay is not supported, so messages are delivered as soon as they are sent.
+	assert.NotEqual(t, int64(0), rcvMsg.GetJMSDeliveryTime())
+	assert.Equal(t, rcvMsg.GetJMSTimestamp(), rcvMsg.GetJMSDeliveryTime())
+
+	// The type can be removed again.
+	assert.Nil(t, rcvMsg.SetJMSType(""))
+	assert.Equal(t, "", rcvMsg.GetJMSType())
+
+}
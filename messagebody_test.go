@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+// order is an example of an application type that is exchanged as JSON.
+type order struct {
+	ID       string  `json:"id"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+/*
+ * Test receiving the body of a message directly as a Golang type.
+ */
+func TestReceiveBody(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(10000)
+	jsonBody := `{"id":"A123","quantity":5,"price":9.99}`
+
+	// Nothing on the queue.
+	gotOrder, errRcv := mqjms.ReceiveBodyNoWait[order](consumer)
+	assert.Nil(t, errRcv)
+	assert.Nil(t, gotOrder)
+
+	// JSON in a TextMessage is decoded into the requested type.
+	errSend := producer.SendString(queue, jsonBody)
+	assert.Nil(t, errSend)
+
+	gotOrder, errRcv = mqjms.ReceiveBody[order](consumer, 1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, gotOrder)
+	assert.Equal(t, order{ID: "A123", Quantity: 5, Price: 9.99}, *gotOrder)
+
+	// JSON in a BytesMessage is decoded in the same way.
+	errSend = producer.SendBytes(queue, []byte(jsonBody))
+	assert.Nil(t, errSend)
+
+	gotOrder, errRcv = mqjms.ReceiveBody[order](consumer, 1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, gotOrder)
+	assert.Equal(t, "A123", gotOrder.ID)
+
+	// Strings and slices of bytes are returned without decoding.
+	errSend = producer.SendString(queue, "plain text")
+	assert.Nil(t, errSend)
+
+	gotStr, errRcv := mqjms.ReceiveBodyNoWait[string](consumer)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, "plain text", *gotStr)
+
+	errSend = producer.SendBytes(queue, []byte{0x01, 0x02})
+	assert.Nil(t, errSend)
+
+	gotBytes, errRcv := mqjms.ReceiveBodyNoWait[[]byte](consumer)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, []byte{0x01, 0x02}, *gotBytes)
+
+	// Asking for a string from a BytesMessage is a MessageFormatException.
+	errSend = producer.SendBytes(queue, []byte{0x01, 0x02})
+	assert.Nil(t, errSend)
+
+	gotStr, errRcv = mqjms.ReceiveBodyNoWait[string](consumer)
+	assert.Nil(t, gotStr)
+	assert.NotNil(t, errRcv)
+	assert.IsType(t, jms20subset.MessageFormatException{}, errRcv)
+	assert.Equal(t, "MQJMS6068", errRcv.GetErrorCode())
+
+	// The message is backed out rather than lost, so it can still be received.
+	rcvMsg, errRcvMsg := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcvMsg)
+	assert.NotNil(t, rcvMsg)
+	switch msg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		assert.Equal(t, []byte{0x01, 0x02}, *msg.ReadBytes())
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+	// A body that can't be decoded is a MessageFormatException.
+	errSend = producer.SendString(queue, "not json")
+	assert.Nil(t, errSend)
+
+	gotOrder, errRcv = mqjms.ReceiveBodyNoWait[order](consumer)
+	assert.Nil(t, gotOrder)
+	assert.NotNil(t, errRcv)
+	assert.IsType(t, jms20subset.MessageFormatException{}, errRcv)
+	assert.Equal(t, "BodyDecodeFailed", errRcv.GetErrorCode())
+
+	gotStr, errRcv = mqjms.ReceiveBodyNoWait[string](consumer)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, "not json", *gotStr)
+
+	// A custom decoder can be used for other formats.
+	errSend = producer.SendString(queue, "A999")
+	assert.Nil(t, errSend)
+
+	idDecoder := func(body []byte, v interface{}) error {
+		o, ok := v.(*order)
+		if !ok {
+			return errors.New("unexpected type")
+		}
+		o.ID = string(body)
+		return nil
+	}
+
+	gotOrder, errRcv = mqjms.ReceiveBodyWithDecoder[order](consumer, 1000, idDecoder)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, "A999", gotOrder.ID)
+
+	// GetBody works on a message that has already been received.
+	msg := context.CreateTextMessageWithString(jsonBody)
+	gotOrder, errRcv = mqjms.GetBody[order](msg)
+	assert.Nil(t, errRcv)
+	assert.Equal(t, 5, gotOrder.Quantity)
+
+}
+
+/*
+ * Test that a message whose body can never be converted is moved to the backout
+ * requeue queue once it reaches the backout threshold, rather than being received
+ * again on every call.
+ */
+func TestReceiveBodyBackout(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	attrs, errInq := context.InquireQueue(queue)
+	assert.Nil(t, errInq)
+	if attrs.BackoutThreshold == 0 || attrs.BackoutRequeueQName == "" {
+		t.Skip("Skipping test as DEV.QUEUE.1 does not have BOTHRESH and BOQNAME set")
+	}
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	sendMsg := context.CreateTextMessageWithString("not json")
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	// Each attempt fails, until the message has been backed out as many times as
+	// the backout threshold.
+	for i := 0; i <= attrs.BackoutThreshold; i++ {
+		gotOrder, errRcv := mqjms.ReceiveBodyNoWait[order](consumer)
+		assert.Nil(t, gotOrder)
+		assert.NotNil(t, errRcv)
+	}
+
+	// The message is no longer on the queue.
+	gotStr, errRcv := mqjms.ReceiveBodyNoWait[string](consumer)
+	assert.Nil(t, errRcv)
+	assert.Nil(t, gotStr)
+
+	// Instead it is on the backout requeue queue, with the same message ID.
+	backoutConsumer, errCons := context.CreateConsumerWithSelector(context.CreateQueue(attrs.BackoutRequeueQName),
+		"JMSMessageID = '"+sendMsg.GetJMSMessageID()+"'")
+	assert.Nil(t, errCons)
+	if backoutConsumer != nil {
+		defer backoutConsumer.Close()
+	}
+
+	backoutMsg, errRcv := backoutConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, backoutMsg)
+
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test closing messages once the application has finished with them, so that
+ * their message handles are released without waiting for garbage collection.
+ */
+func TestMessageClose(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().SetTimeToLive(20000)
+
+	for i := 0; i < 100; i++ {
+
+		sendMsg := context.CreateTextMessageWithString("close msg")
+		propErr := sendMsg.SetIntProperty("index", i)
+		assert.Nil(t, propErr)
+
+		errSend := producer.Send(queue, sendMsg)
+		assert.Nil(t, errSend)
+
+		// The message has been sent, so it is no longer needed.
+		sendMsg.Close()
+
+		rcvMsg, errRcv := consumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvMsg)
+
+		if rcvMsg != nil {
+			gotIndex, propErr := rcvMsg.GetIntProperty("index")
+			assert.Nil(t, propErr)
+			assert.Equal(t, i, gotIndex)
+
+			rcvMsg.Close()
+
+			// Closing a message more than once is allowed.
+			rcvMsg.Close()
+		}
+	}
+
+}
+
+/*
+ * Test that messages which are garbage collected without being closed are
+ * reported, along with where they were created, when leak debugging is enabled.
+ */
+func TestMessageHandleLeakDebug(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	reports := make(chan string, 100)
+	mqjms.SetMessageHandleLeakReporter(func(stack string) {
+		select {
+		case reports <- stack:
+		default:
+		}
+	})
+
+	mqjms.SetMessageHandleLeakDebug(true)
+	defer func() {
+		mqjms.SetMessageHandleLeakDebug(false)
+		mqjms.SetMessageHandleLeakReporter(nil)
+	}()
+
+	// A message that is closed isn't reported.
+	createClosedMessage(context)
+
+	// Whereas one that isn't closed is.
+	createLeakedMessage(context)
+
+	var leakStack string
+	for i := 0; i < 20 && leakStack == ""; i++ {
+
+		runtime.GC()
+
+		select {
+		case stack := <-reports:
+			assert.NotContains(t, stack, "createClosedMessage")
+			if strings.Contains(stack, "createLeakedMessage") {
+				leakStack = stack
+			}
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	assert.Contains(t, leakStack, "createLeakedMessage")
+
+}
+
+// createClosedMessage creates a message that is closed before it goes out of scope.
+func createClosedMessage(context jms20subset.JMSContext) {
+	msg := context.CreateTextMessageWithString("closed message")
+	msg.Close()
+}
+
+// createLeakedMessage creates a message that goes out of scope without being closed.
+func createLeakedMessage(context jms20subset.JMSContext) {
+	context.CreateTextMessageWithString("leaked message")
+}
@@ -12,6 +12,7 @@ package main
 import (
 	"testing"
 
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
 	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
 	"github.com/stretchr/testify/assert"
 )
@@ -150,3 +151,92 @@ func TestMessageGroup(t *testing.T) {
 	*/
 
 }
+
+/*
+ * Test sending a message group using a GroupProducer, and receiving it in
+ * order only once the whole group is available.
+ */
+func TestGroupProducer(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Set up objects for send/receive
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateGroupConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	groupProducer, errGrp := context.CreateProducer().SetTimeToLive(10000).CreateGroupProducer(queue)
+	assert.Nil(t, errGrp)
+	if groupProducer != nil {
+		defer groupProducer.Close()
+	}
+	assert.Equal(t, "", groupProducer.GetGroupID())
+
+	// Send the first two messages of the group.
+	txtMsg1 := context.CreateTextMessageWithString("part 1")
+	errSend := groupProducer.Send(txtMsg1)
+	assert.Nil(t, errSend)
+	groupID := groupProducer.GetGroupID()
+	assert.Equal(t, 48, len(groupID))
+
+	txtMsg2 := context.CreateTextMessageWithString("part 2")
+	errSend = groupProducer.Send(txtMsg2)
+	assert.Nil(t, errSend)
+	assert.Equal(t, groupID, groupProducer.GetGroupID())
+
+	// The group is not complete, so nothing can be received yet.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+	// Complete the group.
+	txtMsg3 := context.CreateTextMessageWithString("part 3")
+	errSend = groupProducer.SendLast(txtMsg3)
+	assert.Nil(t, errSend)
+	assert.Equal(t, groupID, groupProducer.GetGroupID())
+
+	// Now the messages are received in order.
+	for i, sentMsg := range []jms20subset.TextMessage{txtMsg1, txtMsg2, txtMsg3} {
+
+		rcvMsg, errRcv = consumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, rcvMsg)
+		assert.Equal(t, sentMsg.GetJMSMessageID(), rcvMsg.GetJMSMessageID())
+
+		gotGroupIDValue, gotErr := rcvMsg.GetStringProperty("JMSXGroupID")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, groupID, *gotGroupIDValue)
+		gotSeqValue, gotErr := rcvMsg.GetIntProperty("JMSXGroupSeq")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, i+1, gotSeqValue)
+		gotLastMsgValue, gotErr := rcvMsg.GetBooleanProperty("JMS_IBM_Last_Msg_In_Group")
+		assert.Nil(t, gotErr)
+		assert.Equal(t, i == 2, gotLastMsgValue)
+	}
+
+	// The next message starts a new group.
+	errSend = groupProducer.SendLast(context.CreateTextMessageWithString("only part"))
+	assert.Nil(t, errSend)
+	assert.NotEqual(t, groupID, groupProducer.GetGroupID())
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+	gotSeqValue, gotErr := rcvMsg.GetIntProperty("JMSXGroupSeq")
+	assert.Nil(t, gotErr)
+	assert.Equal(t, 1, gotSeqValue)
+
+}
@@ -1674,3 +1674,241 @@ func TestPropertyConversionDouble(t *testing.T) {
 	assert.Equal(t, false, gotBoolLargeNegDecimalValue)
 
 }
+
+/*
+ * Test the byte, short, long, float and byte array property types, which are
+ * stored with the matching MQ property type.
+ */
+func TestPropertyTypedValues(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	msg := context.CreateTextMessageWithString("TypedPropertyMsg")
+
+	byteValue := int8(-12)
+	shortValue := int16(3021)
+	longValue := int64(1718035200123) // a typical Java timestamp
+	floatValue := float32(3.25)
+	bytesValue := []byte{0x00, 0x01, 0xFE, 0xFF}
+
+	// Test the empty values before the properties are set.
+	gotByte, propErr := msg.GetByteProperty("myByte")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int8(0), gotByte)
+	gotBytes, propErr := msg.GetBytesProperty("myBytes")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotBytes)
+	gotObject, propErr := msg.GetObjectProperty("myObject")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotObject)
+
+	assert.Nil(t, msg.SetByteProperty("myByte", byteValue))
+	assert.Nil(t, msg.SetShortProperty("myShort", shortValue))
+	assert.Nil(t, msg.SetLongProperty("myLong", longValue))
+	assert.Nil(t, msg.SetFloatProperty("myFloat", floatValue))
+	assert.Nil(t, msg.SetBytesProperty("myBytes", bytesValue))
+	assert.Nil(t, msg.SetObjectProperty("myObjectInt32", int32(-7)))
+	assert.Nil(t, msg.SetObjectProperty("myObjectString", "objectString"))
+
+	// Set and then unset a bytes property and an object property
+	assert.Nil(t, msg.SetBytesProperty("myUnsetBytes", bytesValue))
+	assert.Nil(t, msg.SetBytesProperty("myUnsetBytes", nil))
+	assert.Nil(t, msg.SetObjectProperty("myUnsetObject", int64(5)))
+	assert.Nil(t, msg.SetObjectProperty("myUnsetObject", nil))
+
+	// Unsupported object types are rejected.
+	objErr := msg.SetObjectProperty("myStruct", struct{}{})
+	assert.NotNil(t, objErr)
+	assert.Equal(t, "MQJMS_E_UNSUPPORTED_TYPE", objErr.GetReason())
+
+	// Send the message and get it back again, to check that it roundtripped.
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+	assert.Nil(t, errCons)
+
+	errSend := context.CreateProducer().SetTimeToLive(10000).Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	gotByte, propErr = rcvMsg.GetByteProperty("myByte")
+	assert.Nil(t, propErr)
+	assert.Equal(t, byteValue, gotByte)
+
+	gotShort, propErr := rcvMsg.GetShortProperty("myShort")
+	assert.Nil(t, propErr)
+	assert.Equal(t, shortValue, gotShort)
+
+	gotLong, propErr := rcvMsg.GetLongProperty("myLong")
+	assert.Nil(t, propErr)
+	assert.Equal(t, longValue, gotLong)
+
+	gotFloat, propErr := rcvMsg.GetFloatProperty("myFloat")
+	assert.Nil(t, propErr)
+	assert.Equal(t, floatValue, gotFloat)
+
+	gotBytes, propErr = rcvMsg.GetBytesProperty("myBytes")
+	assert.Nil(t, propErr)
+	assert.Equal(t, bytesValue, gotBytes)
+
+	gotBytes, propErr = rcvMsg.GetBytesProperty("myUnsetBytes")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotBytes)
+
+	// Object properties are returned in the type with which they were set.
+	gotObject, propErr = rcvMsg.GetObjectProperty("myByte")
+	assert.Nil(t, propErr)
+	assert.Equal(t, byteValue, gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myShort")
+	assert.Nil(t, propErr)
+	assert.Equal(t, shortValue, gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myLong")
+	assert.Nil(t, propErr)
+	assert.Equal(t, longValue, gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myFloat")
+	assert.Nil(t, propErr)
+	assert.Equal(t, floatValue, gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myObjectInt32")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int32(-7), gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myObjectString")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "objectString", gotObject)
+	gotObject, propErr = rcvMsg.GetObjectProperty("myUnsetObject")
+	assert.Nil(t, propErr)
+	assert.Nil(t, gotObject)
+
+}
+
+/*
+ * Test the conversion between the byte, short, long, float and byte array
+ * property types and other data types.
+ */
+func TestPropertyConversionTypedValues(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	msg := context.CreateTextMessage()
+
+	msg.SetByteProperty("byteProp", 100)
+	msg.SetShortProperty("shortProp", -2000)
+	msg.SetLongProperty("longProp", -3789753467)
+	msg.SetFloatProperty("floatProp", 1.5)
+	msg.SetBytesProperty("bytesProp", []byte{0x01, 0x02})
+	msgStr := "42"
+	msg.SetStringProperty("strProp", &msgStr)
+	msgBadStr := "notANumber"
+	msg.SetStringProperty("badStrProp", &msgBadStr)
+
+	// Set up objects for send/receive
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+	assert.Nil(t, errCons)
+
+	errSend := context.CreateProducer().SetTimeToLive(10000).Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	// byte can be read as short, int, long and string
+	gotShort, propErr := rcvMsg.GetShortProperty("byteProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int16(100), gotShort)
+	gotInt, propErr := rcvMsg.GetIntProperty("byteProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 100, gotInt)
+	gotLong, propErr := rcvMsg.GetLongProperty("byteProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int64(100), gotLong)
+	gotStr, propErr := rcvMsg.GetStringProperty("byteProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "100", *gotStr)
+
+	// short can be read as int, long and string, but not byte
+	gotInt, propErr = rcvMsg.GetIntProperty("shortProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, -2000, gotInt)
+	gotLong, propErr = rcvMsg.GetLongProperty("shortProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int64(-2000), gotLong)
+	gotStr, propErr = rcvMsg.GetStringProperty("shortProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "-2000", *gotStr)
+	_, propErr = rcvMsg.GetByteProperty("shortProp")
+	assert.NotNil(t, propErr)
+	assert.Equal(t, "MQJMS_E_UNSUPPORTED_TYPE", propErr.GetReason())
+
+	// long can be read as string, but not short
+	gotStr, propErr = rcvMsg.GetStringProperty("longProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "-3789753467", *gotStr)
+	_, propErr = rcvMsg.GetShortProperty("longProp")
+	assert.NotNil(t, propErr)
+
+	// float can be read as double and string, but not long
+	gotDouble, propErr := rcvMsg.GetDoubleProperty("floatProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, float64(1.5), gotDouble)
+	gotStr, propErr = rcvMsg.GetStringProperty("floatProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, "1.5", *gotStr)
+	_, propErr = rcvMsg.GetLongProperty("floatProp")
+	assert.NotNil(t, propErr)
+
+	// byte arrays cannot be converted to any other type
+	_, propErr = rcvMsg.GetStringProperty("bytesProp")
+	assert.NotNil(t, propErr)
+	_, propErr = rcvMsg.GetLongProperty("bytesProp")
+	assert.NotNil(t, propErr)
+
+	// strings are parsed into each of the types
+	gotByte, propErr := rcvMsg.GetByteProperty("strProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int8(42), gotByte)
+	gotShort, propErr = rcvMsg.GetShortProperty("strProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int16(42), gotShort)
+	gotLong, propErr = rcvMsg.GetLongProperty("strProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int64(42), gotLong)
+	gotFloat, propErr := rcvMsg.GetFloatProperty("strProp")
+	assert.Nil(t, propErr)
+	assert.Equal(t, float32(42), gotFloat)
+	_, propErr = rcvMsg.GetBytesProperty("strProp")
+	assert.NotNil(t, propErr)
+
+	_, propErr = rcvMsg.GetLongProperty("badStrProp")
+	assert.NotNil(t, propErr)
+	assert.Equal(t, "MQJMS_E_BAD_TYPE", propErr.GetReason())
+
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test getting the details of the provider and the connected queue manager.
+ */
+func TestGetMetaData(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	metaData, errMeta := context.GetMetaData()
+	assert.Nil(t, errMeta)
+
+	// Details of the provider.
+	assert.Equal(t, "2.0", metaData.JMSVersion)
+	assert.Equal(t, 2, metaData.JMSMajorVersion)
+	assert.Equal(t, 0, metaData.JMSMinorVersion)
+	assert.Equal(t, mqjms.ContextImpl_PROVIDER_NAME, metaData.JMSProviderName)
+	assert.NotEqual(t, "", metaData.ProviderVersion)
+	assert.NotEqual(t, "", metaData.ClientHeaderVersion)
+
+	// Details of the queue manager.
+	assert.Equal(t, cf.QMName, metaData.QueueManagerName)
+	assert.NotEqual(t, "", metaData.QueueManagerIdentifier)
+	assert.GreaterOrEqual(t, metaData.CommandLevel, int(ibmmq.MQCMDL_LEVEL_800))
+	assert.NotEqual(t, "", metaData.PlatformName)
+	assert.Greater(t, metaData.CodedCharSetId, 0)
+
+	// Any supported queue manager provides these capabilities.
+	assert.True(t, metaData.SupportsAsyncPut())
+	assert.True(t, metaData.SupportsSharedSubscriptions())
+
+}
@@ -0,0 +1,451 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// Values of the TranState field of an IMSHeader.
+const IMSHeader_TRANSTATE_IN_CONVERSATION byte = 'C'
+const IMSHeader_TRANSTATE_NOT_IN_CONVERSATION byte = ' '
+const IMSHeader_TRANSTATE_ARCHITECTED byte = 'A'
+
+// Values of the CommitMode field of an IMSHeader.
+const IMSHeader_COMMITMODE_COMMIT_THEN_SEND byte = '0'
+const IMSHeader_COMMITMODE_SEND_THEN_COMMIT byte = '1'
+
+// Values of the SecurityScope field of an IMSHeader.
+const IMSHeader_SECURITYSCOPE_CHECK byte = 'C'
+const IMSHeader_SECURITYSCOPE_FULL byte = 'F'
+
+// Structure identifiers at the start of each header.
+const cicsHeaderStrucID string = "CIH "
+const imsHeaderStrucID string = "IIH "
+
+// CICSHeader is the CICS bridge header (MQCIH), which is put in front of the
+// data of a message that drives a CICS program or transaction through the
+// CICS bridge, and is returned in front of the data of the reply.
+//
+// Text fields are padded with spaces, or truncated, to the length of the field
+// in the header.
+type CICSHeader struct {
+	Encoding           int32  // Numeric encoding of the data that follows the header
+	CodedCharSetId     int32  // Character set of the data that follows the header
+	Format             string // Format of the data that follows the header
+	Flags              int32  // Flags, for example ibmmq.MQCIH_PASS_EXPIRATION
+	ReturnCode         int32  // Return code from the bridge, for example ibmmq.MQCRC_OK
+	CompCode           int32  // MQ completion code or CICS EIBRESP
+	Reason             int32  // MQ reason code or CICS EIBRESP2
+	UOWControl         int32  // Unit of work control, for example ibmmq.MQCUOWC_ONLY
+	GetWaitInterval    int32  // Wait interval for an MQGET by the bridge task
+	LinkType           int32  // ibmmq.MQCLT_PROGRAM or ibmmq.MQCLT_TRANSACTION
+	OutputDataLength   int32  // Length of the COMMAREA data returned by the program
+	FacilityKeepTime   int32  // Bridge facility release time, in seconds
+	ADSDescriptor      int32  // Send/receive ADS descriptor, for example ibmmq.MQCADSD_NONE
+	ConversationalTask int32  // ibmmq.MQCCT_YES or ibmmq.MQCCT_NO
+	TaskEndStatus      int32  // Status at the end of the task, for example ibmmq.MQCTES_COMMIT
+	Facility           []byte // 8 byte bridge facility token
+	Function           string // MQ call name or CICS EIBFN function
+	AbendCode          string // Abend code returned by the bridge
+	Authenticator      string // Password or passticket
+	ReplyToFormat      string // Format of the reply message
+	RemoteSysId        string // Remote CICS system ID to use
+	RemoteTransId      string // CICS RTRANSID to use
+	TransactionId      string // Transaction to attach
+	FacilityLike       string // Terminal emulated attributes
+	AttentionId        string // AID key
+	StartCode          string // Transaction start code
+	CancelCode         string // Abend transaction code
+	NextTransactionId  string // Next transaction to attach
+	CursorPosition     int32  // Cursor position
+	ErrorOffset        int32  // Offset of the error in the message
+	InputItem          int32  // Reserved
+}
+
+// IMSHeader is the IMS bridge header (MQIIH), which is put in front of the data
+// of a message that drives an IMS transaction through the IMS bridge, and is
+// returned in front of the data of the reply.
+//
+// Text fields are padded with spaces, or truncated, to the length of the field
+// in the header.
+type IMSHeader struct {
+	Encoding       int32  // Numeric encoding of the data that follows the header
+	CodedCharSetId int32  // Character set of the data that follows the header
+	Format         string // Format of the data that follows the header
+	Flags          int32  // Flags, for example ibmmq.MQIIH_PASS_EXPIRATION
+	LTermOverride  string // Logical terminal override
+	MFSMapName     string // Message format services map name
+	ReplyToFormat  string // Format of the reply message
+	Authenticator  string // RACF password or passticket
+	TranInstanceId []byte // 16 byte transaction instance identifier
+	TranState      byte   // One of the IMSHeader_TRANSTATE_* values
+	CommitMode     byte   // One of the IMSHeader_COMMITMODE_* values
+	SecurityScope  byte   // One of the IMSHeader_SECURITYSCOPE_* values
+}
+
+// NewCICSHeader returns a version 2 CICS bridge header with the default values
+// for each field. The data that follows the header is described as having the
+// native encoding, and the same character set as the header.
+func NewCICSHeader() *CICSHeader {
+
+	return &CICSHeader{
+		Encoding:         ibmmq.MQENC_NATIVE,
+		CodedCharSetId:   ibmmq.MQCCSI_INHERIT,
+		Format:           ibmmq.MQFMT_NONE,
+		ReturnCode:       ibmmq.MQCRC_OK,
+		CompCode:         ibmmq.MQCC_OK,
+		Reason:           ibmmq.MQRC_NONE,
+		UOWControl:       ibmmq.MQCUOWC_ONLY,
+		GetWaitInterval:  ibmmq.MQCGWI_DEFAULT,
+		LinkType:         ibmmq.MQCLT_PROGRAM,
+		OutputDataLength: ibmmq.MQCODL_AS_INPUT,
+		ADSDescriptor:    ibmmq.MQCADSD_NONE,
+		TaskEndStatus:    ibmmq.MQCTES_NOSYNC,
+		Facility:         make([]byte, 8),
+	}
+}
+
+// NewIMSHeader returns an IMS bridge header with the default values for each
+// field. The data that follows the header is described as having the native
+// encoding, and the same character set as the header.
+func NewIMSHeader() *IMSHeader {
+
+	return &IMSHeader{
+		Encoding:       ibmmq.MQENC_NATIVE,
+		CodedCharSetId: ibmmq.MQCCSI_INHERIT,
+		Format:         ibmmq.MQFMT_NONE,
+		Flags:          ibmmq.MQIIH_NONE,
+		ReplyToFormat:  ibmmq.MQFMT_NONE,
+		TranInstanceId: make([]byte, 16),
+		TranState:      IMSHeader_TRANSTATE_NOT_IN_CONVERSATION,
+		CommitMode:     IMSHeader_COMMITMODE_COMMIT_THEN_SEND,
+		SecurityScope:  IMSHeader_SECURITYSCOPE_CHECK,
+	}
+}
+
+// Bytes returns the header, with its numeric fields in the native encoding of
+// this platform.
+func (cih *CICSHeader) Bytes() []byte {
+
+	order := byteOrderForEncoding(ibmmq.MQENC_NATIVE)
+	buf := new(bytes.Buffer)
+
+	buf.WriteString(cicsHeaderStrucID)
+	binary.Write(buf, order, ibmmq.MQCIH_VERSION_2)
+	binary.Write(buf, order, ibmmq.MQCIH_LENGTH_2)
+	binary.Write(buf, order, cih.Encoding)
+	binary.Write(buf, order, cih.CodedCharSetId)
+	writeFixedString(buf, cih.Format, ibmmq.MQ_FORMAT_LENGTH)
+	binary.Write(buf, order, cih.Flags)
+	binary.Write(buf, order, cih.ReturnCode)
+	binary.Write(buf, order, cih.CompCode)
+	binary.Write(buf, order, cih.Reason)
+	binary.Write(buf, order, cih.UOWControl)
+	binary.Write(buf, order, cih.GetWaitInterval)
+	binary.Write(buf, order, cih.LinkType)
+	binary.Write(buf, order, cih.OutputDataLength)
+	binary.Write(buf, order, cih.FacilityKeepTime)
+	binary.Write(buf, order, cih.ADSDescriptor)
+	binary.Write(buf, order, cih.ConversationalTask)
+	binary.Write(buf, order, cih.TaskEndStatus)
+	writeFixedBytes(buf, cih.Facility, 8)
+	writeFixedString(buf, cih.Function, 4)
+	writeFixedString(buf, cih.AbendCode, 4)
+	writeFixedString(buf, cih.Authenticator, 8)
+	writeFixedString(buf, "", 8) // Reserved1
+	writeFixedString(buf, cih.ReplyToFormat, ibmmq.MQ_FORMAT_LENGTH)
+	writeFixedString(buf, cih.RemoteSysId, 4)
+	writeFixedString(buf, cih.RemoteTransId, 4)
+	writeFixedString(buf, cih.TransactionId, 4)
+	writeFixedString(buf, cih.FacilityLike, 4)
+	writeFixedString(buf, cih.AttentionId, 4)
+	writeFixedString(buf, cih.StartCode, 4)
+	writeFixedString(buf, cih.CancelCode, 4)
+	writeFixedString(buf, cih.NextTransactionId, 4)
+	writeFixedString(buf, "", 8) // Reserved2
+	writeFixedString(buf, "", 8) // Reserved3
+	binary.Write(buf, order, cih.CursorPosition)
+	binary.Write(buf, order, cih.ErrorOffset)
+	binary.Write(buf, order, cih.InputItem)
+	binary.Write(buf, order, int32(0)) // Reserved4
+
+	return buf.Bytes()
+}
+
+// Bytes returns the header, with its numeric fields in the native encoding of
+// this platform.
+func (iih *IMSHeader) Bytes() []byte {
+
+	order := byteOrderForEncoding(ibmmq.MQENC_NATIVE)
+	buf := new(bytes.Buffer)
+
+	buf.WriteString(imsHeaderStrucID)
+	binary.Write(buf, order, ibmmq.MQIIH_VERSION_1)
+	binary.Write(buf, order, ibmmq.MQIIH_LENGTH_1)
+	binary.Write(buf, order, iih.Encoding)
+	binary.Write(buf, order, iih.CodedCharSetId)
+	writeFixedString(buf, iih.Format, ibmmq.MQ_FORMAT_LENGTH)
+	binary.Write(buf, order, iih.Flags)
+	writeFixedString(buf, iih.LTermOverride, 8)
+	writeFixedString(buf, iih.MFSMapName, 8)
+	writeFixedString(buf, iih.ReplyToFormat, ibmmq.MQ_FORMAT_LENGTH)
+	writeFixedString(buf, iih.Authenticator, 8)
+	writeFixedBytes(buf, iih.TranInstanceId, 16)
+	buf.WriteByte(iih.TranState)
+	buf.WriteByte(iih.CommitMode)
+	buf.WriteByte(iih.SecurityScope)
+	buf.WriteByte(' ') // Reserved
+
+	return buf.Bytes()
+}
+
+// ParseCICSHeader parses the CICS bridge header at the start of the buffer,
+// whose numeric fields are in the specified encoding, and returns the header
+// and its length. Both version 1 and version 2 headers are accepted.
+func ParseCICSHeader(buf []byte, encoding int32) (*CICSHeader, int, error) {
+
+	if len(buf) < int(ibmmq.MQCIH_LENGTH_1) || string(buf[0:4]) != cicsHeaderStrucID {
+		return nil, 0, errors.New("Buffer does not start with an MQCIH")
+	}
+
+	order := byteOrderForEncoding(encoding)
+	r := bytes.NewReader(buf[4:])
+
+	var version, strucLength int32
+	binary.Read(r, order, &version)
+	binary.Read(r, order, &strucLength)
+
+	if (version != ibmmq.MQCIH_VERSION_1 && version != ibmmq.MQCIH_VERSION_2) ||
+		strucLength < ibmmq.MQCIH_LENGTH_1 || int(strucLength) > len(buf) {
+		return nil, 0, errors.New("Invalid MQCIH version or length")
+	}
+
+	cih := &CICSHeader{}
+	binary.Read(r, order, &cih.Encoding)
+	binary.Read(r, order, &cih.CodedCharSetId)
+	cih.Format = readFixedString(r, ibmmq.MQ_FORMAT_LENGTH)
+	binary.Read(r, order, &cih.Flags)
+	binary.Read(r, order, &cih.ReturnCode)
+	binary.Read(r, order, &cih.CompCode)
+	binary.Read(r, order, &cih.Reason)
+	binary.Read(r, order, &cih.UOWControl)
+	binary.Read(r, order, &cih.GetWaitInterval)
+	binary.Read(r, order, &cih.LinkType)
+	binary.Read(r, order, &cih.OutputDataLength)
+	binary.Read(r, order, &cih.FacilityKeepTime)
+	binary.Read(r, order, &cih.ADSDescriptor)
+	binary.Read(r, order, &cih.ConversationalTask)
+	binary.Read(r, order, &cih.TaskEndStatus)
+	cih.Facility = make([]byte, 8)
+	r.Read(cih.Facility)
+	cih.Function = readFixedString(r, 4)
+	cih.AbendCode = readFixedString(r, 4)
+	cih.Authenticator = readFixedString(r, 8)
+	readFixedString(r, 8) // Reserved1
+	cih.ReplyToFormat = readFixedString(r, ibmmq.MQ_FORMAT_LENGTH)
+	cih.RemoteSysId = readFixedString(r, 4)
+	cih.RemoteTransId = readFixedString(r, 4)
+	cih.TransactionId = readFixedString(r, 4)
+	cih.FacilityLike = readFixedString(r, 4)
+	cih.AttentionId = readFixedString(r, 4)
+	cih.StartCode = readFixedString(r, 4)
+	cih.CancelCode = readFixedString(r, 4)
+	cih.NextTransactionId = readFixedString(r, 4)
+
+	if version >= ibmmq.MQCIH_VERSION_2 && strucLength >= ibmmq.MQCIH_LENGTH_2 {
+		readFixedString(r, 8) // Reserved2
+		readFixedString(r, 8) // Reserved3
+		binary.Read(r, order, &cih.CursorPosition)
+		binary.Read(r, order, &cih.ErrorOffset)
+		binary.Read(r, order, &cih.InputItem)
+	}
+
+	return cih, int(strucLength), nil
+}
+
+// ParseIMSHeader parses the IMS bridge header at the start of the buffer, whose
+// numeric fields are in the specified encoding, and returns the header and its
+// length.
+func ParseIMSHeader(buf []byte, encoding int32) (*IMSHeader, int, error) {
+
+	if len(buf) < int(ibmmq.MQIIH_LENGTH_1) || string(buf[0:4]) != imsHeaderStrucID {
+		return nil, 0, errors.New("Buffer does not start with an MQIIH")
+	}
+
+	order := byteOrderForEncoding(encoding)
+	r := bytes.NewReader(buf[4:])
+
+	var version, strucLength int32
+	binary.Read(r, order, &version)
+	binary.Read(r, order, &strucLength)
+
+	if version != ibmmq.MQIIH_VERSION_1 || strucLength < ibmmq.MQIIH_LENGTH_1 || int(strucLength) > len(buf) {
+		return nil, 0, errors.New("Invalid MQIIH version or length")
+	}
+
+	iih := &IMSHeader{}
+	binary.Read(r, order, &iih.Encoding)
+	binary.Read(r, order, &iih.CodedCharSetId)
+	iih.Format = readFixedString(r, ibmmq.MQ_FORMAT_LENGTH)
+	binary.Read(r, order, &iih.Flags)
+	iih.LTermOverride = readFixedString(r, 8)
+	iih.MFSMapName = readFixedString(r, 8)
+	iih.ReplyToFormat = readFixedString(r, ibmmq.MQ_FORMAT_LENGTH)
+	iih.Authenticator = readFixedString(r, 8)
+	iih.TranInstanceId = make([]byte, 16)
+	r.Read(iih.TranInstanceId)
+	iih.TranState, _ = r.ReadByte()
+	iih.CommitMode, _ = r.ReadByte()
+	iih.SecurityScope, _ = r.ReadByte()
+
+	return iih, int(strucLength), nil
+}
+
+// CreateCICSBridgeMessage creates a message that drives a CICS program or
+// transaction through the CICS bridge, with the header in front of the data.
+//
+// The format of the message is set to MQCICS, and the Format field of the
+// header describes the data that follows it.
+func CreateCICSBridgeMessage(context jms20subset.JMSContext, header *CICSHeader, data []byte) jms20subset.BytesMessage {
+
+	msgBytes := append(header.Bytes(), data...)
+	return createBridgeMessage(context, ibmmq.MQFMT_CICS, msgBytes)
+}
+
+// CreateIMSBridgeMessage creates a message that drives an IMS transaction
+// through the IMS bridge, with the header in front of the data.
+//
+// The format of the message is set to MQIMS, and the Format field of the header
+// describes the data that follows it.
+func CreateIMSBridgeMessage(context jms20subset.JMSContext, header *IMSHeader, data []byte) jms20subset.BytesMessage {
+
+	msgBytes := append(header.Bytes(), data...)
+	return createBridgeMessage(context, ibmmq.MQFMT_IMS, msgBytes)
+}
+
+// createBridgeMessage creates a BytesMessage with the specified format, whose
+// headers are in the native encoding.
+func createBridgeMessage(context jms20subset.JMSContext, format string, msgBytes []byte) jms20subset.BytesMessage {
+
+	msg := context.CreateBytesMessageWithBytes(msgBytes)
+	msg.SetStringProperty("JMS_IBM_Format", &format)
+	msg.SetIntProperty("JMS_IBM_Encoding", int(ibmmq.MQENC_NATIVE))
+
+	return msg
+}
+
+// GetCICSHeader parses the CICS bridge header of a message, such as a reply
+// from the CICS bridge, and returns the header and the data that follows it.
+func GetCICSHeader(msg jms20subset.BytesMessage) (*CICSHeader, []byte, jms20subset.JMSException) {
+
+	msgBytes, encoding, retErr := getBridgeMessageBytes(msg, ibmmq.MQFMT_CICS)
+	if retErr != nil {
+		return nil, nil, retErr
+	}
+
+	cih, headerLen, err := ParseCICSHeader(msgBytes, encoding)
+	if err != nil {
+		return nil, nil, jms20subset.CreateJMSException("InvalidCICSHeader", "InvalidCICSHeader", err)
+	}
+
+	return cih, msgBytes[headerLen:], nil
+}
+
+// GetIMSHeader parses the IMS bridge header of a message, such as a reply from
+// the IMS bridge, and returns the header and the data that follows it.
+func GetIMSHeader(msg jms20subset.BytesMessage) (*IMSHeader, []byte, jms20subset.JMSException) {
+
+	msgBytes, encoding, retErr := getBridgeMessageBytes(msg, ibmmq.MQFMT_IMS)
+	if retErr != nil {
+		return nil, nil, retErr
+	}
+
+	iih, headerLen, err := ParseIMSHeader(msgBytes, encoding)
+	if err != nil {
+		return nil, nil, jms20subset.CreateJMSException("InvalidIMSHeader", "InvalidIMSHeader", err)
+	}
+
+	return iih, msgBytes[headerLen:], nil
+}
+
+// getBridgeMessageBytes checks that the message has the expected format, and
+// returns its body along with the encoding of the header.
+func getBridgeMessageBytes(msg jms20subset.BytesMessage, format string) ([]byte, int32, jms20subset.JMSException) {
+
+	msgFormat, retErr := msg.GetStringProperty("JMS_IBM_Format")
+	if retErr != nil {
+		return nil, 0, retErr
+	}
+
+	if msgFormat == nil || strings.TrimSpace(*msgFormat) != format {
+		return nil, 0, jms20subset.CreateJMSException("Message format is not "+format,
+			"InvalidBridgeMessageFormat", nil)
+	}
+
+	encoding, retErr := msg.GetIntProperty("JMS_IBM_Encoding")
+	if retErr != nil {
+		return nil, 0, retErr
+	}
+
+	var msgBytes []byte
+	if bodyBytes := msg.ReadBytes(); bodyBytes != nil {
+		msgBytes = *bodyBytes
+	}
+
+	return msgBytes, int32(encoding), nil
+}
+
+// byteOrderForEncoding returns the byte order of the integers in the specified
+// MQ numeric encoding.
+func byteOrderForEncoding(encoding int32) binary.ByteOrder {
+
+	if encoding&ibmmq.MQENC_INTEGER_MASK == ibmmq.MQENC_INTEGER_REVERSED {
+		return binary.LittleEndian
+	}
+
+	return binary.BigEndian
+}
+
+// writeFixedString writes a string into a fixed length field, padded with spaces.
+func writeFixedString(buf *bytes.Buffer, value string, length int32) {
+
+	field := make([]byte, length)
+	n := copy(field, value)
+	for i := n; i < len(field); i++ {
+		field[i] = ' '
+	}
+
+	buf.Write(field)
+}
+
+// writeFixedBytes writes a byte array into a fixed length field, padded with nulls.
+func writeFixedBytes(buf *bytes.Buffer, value []byte, length int32) {
+
+	field := make([]byte, length)
+	copy(field, value)
+
+	buf.Write(field)
+}
+
+// readFixedString reads a fixed length field, and trims the padding from it.
+func readFixedString(r *bytes.Reader, length int32) string {
+
+	field := make([]byte, length)
+	r.Read(field)
+
+	return strings.TrimRight(string(field), " \x00")
+}
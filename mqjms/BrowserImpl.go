@@ -10,6 +10,9 @@
 package mqjms
 
 import (
+	"errors"
+	"iter"
+
 	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
 )
@@ -18,6 +21,8 @@ import (
 // to peek at messages on a queue without destructively consuming them.
 type BrowserImpl struct {
 	browseOption *int32
+	lockMessages bool
+	cooperative  bool
 	ConsumerImpl // Browser is a specialized form of consumer
 }
 
@@ -35,13 +40,50 @@ func (browser *BrowserImpl) GetEnumeration() (jms20subset.MessageIterator, jms20
 
 }
 
+// GetQueue returns the queue associated with this QueueBrowser.
+func (browser *BrowserImpl) GetQueue() jms20subset.Queue {
+
+	// Browsers can only be created for queues, but check anyway in case an
+	// application provided its own Destination implementation.
+	if queue, ok := browser.dest.(jms20subset.Queue); ok {
+		return queue
+	}
+
+	return nil
+}
+
+// GetMessageSelector returns the message selector for this QueueBrowser,
+// or an empty string if no selector was specified.
+func (browser *BrowserImpl) GetMessageSelector() string {
+	return browser.selector
+}
+
 // GetNext returns the next Message that is available
 // or else nil if no messages are available.
 func (browser *BrowserImpl) GetNext() (jms20subset.Message, jms20subset.JMSException) {
 
-	// Like a ReceiveNoWait, but with Browse turned on.
+	// Like a ReceiveNoWait, but with Browse turned on. Any selector is applied
+	// by receiveInternal, so each browse step moves on to the next message that
+	// matches the selector.
 	gmo := ibmmq.NewMQGMO()
-	gmo.Options |= *browser.browseOption
+	if browser.cooperative {
+
+		// Cooperative browsers always start again from the first message that
+		// hasn't been marked by any of the cooperating browsers, so that they pick
+		// up messages that arrive after they reached the end of the queue, and mark
+		// the message so that the others skip over it.
+		gmo.Options |= ibmmq.MQGMO_BROWSE_FIRST
+		gmo.Options |= ibmmq.MQGMO_UNMARKED_BROWSE_MSG | ibmmq.MQGMO_MARK_BROWSE_CO_OP
+
+	} else {
+		gmo.Options |= *browser.browseOption
+	}
+
+	// Lock the message so that it isn't visible to other applications until
+	// we browse the next message, or consume this one.
+	if browser.lockMessages {
+		gmo.Options |= ibmmq.MQGMO_LOCK
+	}
 
 	msg, err := browser.receiveInternal(gmo)
 
@@ -54,3 +96,138 @@ func (browser *BrowserImpl) GetNext() (jms20subset.Message, jms20subset.JMSExcep
 
 	return msg, err
 }
+
+// ConsumeCurrent destructively receives the message that is under the browse
+// cursor, which is the message that was most recently returned by GetNext.
+//
+// The message is received under syncpoint if the JMSContext is transacted. If the
+// message has been removed by another application since it was browsed then an
+// exception is returned with the MQRC_NO_MSG_UNDER_CURSOR reason code, which can
+// be avoided by calling SetLockMessages(true) before browsing.
+func (browser *BrowserImpl) ConsumeCurrent() (jms20subset.Message, jms20subset.JMSException) {
+
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options |= ibmmq.MQGMO_MSG_UNDER_CURSOR
+
+	return browser.receiveInternal(gmo)
+}
+
+// SetLockMessages controls whether each message that is browsed is locked
+// until the next message is browsed, so that no other application can
+// browse or receive it while it is being inspected. The lock is also released
+// when the message is consumed using ConsumeCurrent or the browser is closed.
+func (browser *BrowserImpl) SetLockMessages(lock bool) jms20subset.QueueBrowser {
+	browser.lockMessages = lock
+	return browser
+}
+
+// GetLockMessages returns whether messages are locked as they are browsed.
+func (browser *BrowserImpl) GetLockMessages() bool {
+	return browser.lockMessages
+}
+
+// All returns a sequence of the messages on the queue, for use in a
+// "for msg, err := range browser.All()" loop. Each time the sequence is iterated
+// it starts again from the first message on the queue, and any selector of the
+// browser is applied in the same way as for GetNext.
+//
+// If an error occurs it is returned as the final element of the sequence. If
+// messages are being locked then the lock on the current message is released
+// when the loop is exited.
+func (browser *BrowserImpl) All() iter.Seq2[jms20subset.Message, error] {
+
+	return func(yield func(jms20subset.Message, error) bool) {
+
+		brse := int32(ibmmq.MQGMO_BROWSE_FIRST)
+		browser.browseOption = &brse
+
+		browser.browseRemaining(yield)
+	}
+}
+
+// AllFrom returns a sequence of the messages on the queue starting from the
+// message with the specified JMSMessageID, for example to carry on from where
+// an earlier browse finished. The selector of the browser is not applied to
+// that first message, only to the messages that follow it.
+//
+// If the message is no longer on the queue then a MessageNotFound exception is
+// returned as the only element of the sequence.
+func (browser *BrowserImpl) AllFrom(msgID string) iter.Seq2[jms20subset.Message, error] {
+
+	return func(yield func(jms20subset.Message, error) bool) {
+
+		// Move the browse cursor onto the requested message by browsing for it
+		// using the same object handle, but with a message ID selector.
+		positioner := browser.ConsumerImpl
+		positioner.selector = "JMSMessageID = '" + msgID + "'"
+
+		gmo := ibmmq.NewMQGMO()
+		gmo.Options |= ibmmq.MQGMO_BROWSE_FIRST
+		if browser.lockMessages {
+			gmo.Options |= ibmmq.MQGMO_LOCK
+		}
+
+		msg, jmsErr := positioner.receiveInternal(gmo)
+		if jmsErr == nil && msg == nil {
+			jmsErr = jms20subset.CreateJMSException("MessageNotFound", "MessageNotFound",
+				errors.New("No message was found with JMSMessageID "+msgID))
+		}
+
+		if jmsErr != nil {
+			yield(nil, jmsErr)
+			return
+		}
+
+		brse := int32(ibmmq.MQGMO_BROWSE_NEXT)
+		browser.browseOption = &brse
+
+		if !yield(msg, nil) {
+			browser.unlockCurrent()
+			return
+		}
+
+		browser.browseRemaining(yield)
+	}
+}
+
+// browseRemaining yields each of the messages from the current position of the
+// browse cursor onwards, until the end of the queue is reached or the loop is
+// exited.
+func (browser *BrowserImpl) browseRemaining(yield func(jms20subset.Message, error) bool) {
+
+	defer browser.unlockCurrent()
+
+	for {
+		msg, jmsErr := browser.GetNext()
+
+		if jmsErr != nil {
+			yield(nil, jmsErr)
+			return
+		}
+
+		if msg == nil || !yield(msg, nil) {
+			return
+		}
+	}
+}
+
+// unlockCurrent releases the lock on the message under the browse cursor, if
+// messages are being locked as they are browsed.
+func (browser *BrowserImpl) unlockCurrent() {
+
+	if !browser.lockMessages {
+		return
+	}
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	browser.ctx.ctxLock.Lock()
+	defer browser.ctx.ctxLock.Unlock()
+
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options = ibmmq.MQGMO_UNLOCK
+
+	// MQRC_NO_MSG_LOCKED is returned if the message has already been consumed,
+	// or was unlocked when the end of the queue was reached, which is fine.
+	browser.qObject.Get(ibmmq.NewMQMD(), gmo, nil)
+}
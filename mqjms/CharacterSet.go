@@ -0,0 +1,246 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// CCSIDs of the character sets that are converted by this library, in addition
+// to UTF-8 (1208) which is the character set used by Go strings.
+const (
+	ccsidEBCDIC037  int32 = 37    // EBCDIC US/Canada
+	ccsidEBCDIC500  int32 = 500   // EBCDIC International
+	ccsidLatin1     int32 = 819   // ISO 8859-1
+	ccsidEBCDIC1047 int32 = 1047  // EBCDIC Latin 1/Open Systems
+	ccsidUTF16      int32 = 1200  // UTF-16, in the byte order of the MQMD Encoding
+	ccsidUTF16BE    int32 = 1201  // UTF-16 big-endian
+	ccsidUTF16LE    int32 = 1202  // UTF-16 little-endian
+	ccsidUTF8       int32 = 1208  // UTF-8
+	ccsidUCS2       int32 = 13488 // UCS-2, in the byte order of the MQMD Encoding
+	ccsidUTF16Alt   int32 = 17584 // UTF-16 (CCSID 1200 with the euro sign), in the byte order of the MQMD Encoding
+)
+
+// ebcdic037ToLatin1 maps each byte of EBCDIC code page 037 to the ISO 8859-1
+// character (and therefore Unicode code point) that it represents.
+var ebcdic037ToLatin1 = [256]byte{
+	0x00, 0x01, 0x02, 0x03, 0x9c, 0x09, 0x86, 0x7f, 0x97, 0x8d, 0x8e, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, // 0x00
+	0x10, 0x11, 0x12, 0x13, 0x9d, 0x85, 0x08, 0x87, 0x18, 0x19, 0x92, 0x8f, 0x1c, 0x1d, 0x1e, 0x1f, // 0x10
+	0x80, 0x81, 0x82, 0x83, 0x84, 0x0a, 0x17, 0x1b, 0x88, 0x89, 0x8a, 0x8b, 0x8c, 0x05, 0x06, 0x07, // 0x20
+	0x90, 0x91, 0x16, 0x93, 0x94, 0x95, 0x96, 0x04, 0x98, 0x99, 0x9a, 0x9b, 0x14, 0x15, 0x9e, 0x1a, // 0x30
+	0x20, 0xa0, 0xe2, 0xe4, 0xe0, 0xe1, 0xe3, 0xe5, 0xe7, 0xf1, 0xa2, 0x2e, 0x3c, 0x28, 0x2b, 0x7c, // 0x40
+	0x26, 0xe9, 0xea, 0xeb, 0xe8, 0xed, 0xee, 0xef, 0xec, 0xdf, 0x21, 0x24, 0x2a, 0x29, 0x3b, 0xac, // 0x50
+	0x2d, 0x2f, 0xc2, 0xc4, 0xc0, 0xc1, 0xc3, 0xc5, 0xc7, 0xd1, 0xa6, 0x2c, 0x25, 0x5f, 0x3e, 0x3f, // 0x60
+	0xf8, 0xc9, 0xca, 0xcb, 0xc8, 0xcd, 0xce, 0xcf, 0xcc, 0x60, 0x3a, 0x23, 0x40, 0x27, 0x3d, 0x22, // 0x70
+	0xd8, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0xab, 0xbb, 0xf0, 0xfd, 0xfe, 0xb1, // 0x80
+	0xb0, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f, 0x70, 0x71, 0x72, 0xaa, 0xba, 0xe6, 0xb8, 0xc6, 0xa4, // 0x90
+	0xb5, 0x7e, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7a, 0xa1, 0xbf, 0xd0, 0xdd, 0xde, 0xae, // 0xA0
+	0x5e, 0xa3, 0xa5, 0xb7, 0xa9, 0xa7, 0xb6, 0xbc, 0xbd, 0xbe, 0x5b, 0x5d, 0xaf, 0xa8, 0xb4, 0xd7, // 0xB0
+	0x7b, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49, 0xad, 0xf4, 0xf6, 0xf2, 0xf3, 0xf5, // 0xC0
+	0x7d, 0x4a, 0x4b, 0x4c, 0x4d, 0x4e, 0x4f, 0x50, 0x51, 0x52, 0xb9, 0xfb, 0xfc, 0xf9, 0xfa, 0xff, // 0xD0
+	0x5c, 0xf7, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5a, 0xb2, 0xd4, 0xd6, 0xd2, 0xd3, 0xd5, // 0xE0
+	0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0xb3, 0xdb, 0xdc, 0xd9, 0xda, 0x9f, // 0xF0
+}
+
+// ebcdicTables holds the mapping to ISO 8859-1 for each supported EBCDIC code
+// page, which are all permutations of the same set of characters.
+var ebcdicTables = map[int32]*[256]byte{
+	ccsidEBCDIC037:  &ebcdic037ToLatin1,
+	ccsidEBCDIC500:  ebcdicVariant(map[byte]byte{0x4A: '[', 0x4F: '!', 0x5A: ']', 0x5F: '^', 0xB0: 0xA2, 0xBA: 0xAC, 0xBB: '|'}),
+	ccsidEBCDIC1047: ebcdicVariant(map[byte]byte{0x5F: '^', 0xAD: '[', 0xB0: 0xAC, 0xBA: 0xDD, 0xBB: 0xA8, 0xBD: ']'}),
+}
+
+// ebcdicVariant returns the table for a code page that differs from code page
+// 037 in the specified positions.
+func ebcdicVariant(differences map[byte]byte) *[256]byte {
+
+	table := ebcdic037ToLatin1
+	for ebcdic, latin1 := range differences {
+		table[ebcdic] = latin1
+	}
+
+	return &table
+}
+
+// decodeText converts the body of a text message from the character set and
+// encoding described by the MQMD into a Go string.
+//
+// Bodies in UTF-8, or in character sets that are not supported by this library
+// but that only contain ASCII characters, are returned unchanged. Any other body
+// in an unsupported character set is rejected, rather than returning text that
+// has not been converted.
+func decodeText(body []byte, ccsid int32, encoding int32) (string, error) {
+
+	if table, ok := ebcdicTables[ccsid]; ok {
+		runes := make([]rune, len(body))
+		for i, b := range body {
+			runes[i] = rune(table[b])
+		}
+		return string(runes), nil
+	}
+
+	switch ccsid {
+	case ccsidLatin1:
+		runes := make([]rune, len(body))
+		for i, b := range body {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+
+	case ccsidUTF16, ccsidUTF16BE, ccsidUTF16LE, ccsidUCS2, ccsidUTF16Alt:
+		if len(body)%2 != 0 {
+			return "", errors.New("UTF-16 message body has an odd number of bytes")
+		}
+
+		bigEndian := isUTF16BigEndian(ccsid, encoding)
+
+		// A byte order mark takes precedence over the CCSID and encoding.
+		if len(body) >= 2 && body[0] == 0xFE && body[1] == 0xFF {
+			bigEndian = true
+			body = body[2:]
+		} else if len(body) >= 2 && body[0] == 0xFF && body[1] == 0xFE {
+			bigEndian = false
+			body = body[2:]
+		}
+
+		units := make([]uint16, len(body)/2)
+		for i := range units {
+			if bigEndian {
+				units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+			} else {
+				units[i] = uint16(body[2*i+1])<<8 | uint16(body[2*i])
+			}
+		}
+		return string(utf16.Decode(units)), nil
+
+	case ibmmq.MQCCSI_Q_MGR, ibmmq.MQCCSI_INHERIT, ccsidUTF8:
+		return string(body), nil
+	}
+
+	// Bodies in other character sets are only accepted if they don't need to be
+	// converted, in the same way as encodeText. ASCII is a subset of UTF-8, so this
+	// also allows for a message that was labelled with the wrong CCSID.
+	if utf8.Valid(body) {
+		return string(body), nil
+	}
+
+	return "", errors.New("Conversion from CCSID " + strconv.Itoa(int(ccsid)) + " is not supported")
+}
+
+// encodeText converts a Go string into the character set and encoding that is
+// described by the MQMD of a text message. Characters that cannot be represented
+// in a single byte character set are replaced by a question mark.
+func encodeText(text string, ccsid int32, encoding int32) ([]byte, error) {
+
+	if table, ok := ebcdicTables[ccsid]; ok {
+
+		var fromLatin1 [256]byte
+		for ebcdic, latin1 := range table {
+			fromLatin1[latin1] = byte(ebcdic)
+		}
+
+		body := make([]byte, 0, len(text))
+		for _, r := range text {
+			if r > 0xFF {
+				r = '?'
+			}
+			body = append(body, fromLatin1[r])
+		}
+		return body, nil
+	}
+
+	switch ccsid {
+	case ibmmq.MQCCSI_Q_MGR, ibmmq.MQCCSI_INHERIT, ccsidUTF8:
+		return []byte(text), nil
+
+	case ccsidLatin1:
+		body := make([]byte, 0, len(text))
+		for _, r := range text {
+			if r > 0xFF {
+				r = '?'
+			}
+			body = append(body, byte(r))
+		}
+		return body, nil
+
+	case ccsidUTF16, ccsidUTF16BE, ccsidUTF16LE, ccsidUCS2, ccsidUTF16Alt:
+		bigEndian := isUTF16BigEndian(ccsid, encoding)
+
+		units := utf16.Encode([]rune(text))
+		body := make([]byte, 2*len(units))
+		for i, unit := range units {
+			if bigEndian {
+				body[2*i], body[2*i+1] = byte(unit>>8), byte(unit)
+			} else {
+				body[2*i], body[2*i+1] = byte(unit), byte(unit>>8)
+			}
+		}
+		return body, nil
+	}
+
+	// Text that only contains ASCII characters is sent unchanged in other
+	// character sets, which are typically ASCII based.
+	if isASCII(text) {
+		return []byte(text), nil
+	}
+
+	return nil, errors.New("Conversion to CCSID " + strconv.Itoa(int(ccsid)) + " is not supported")
+}
+
+// isConversionWarning returns true if the queue manager returned a message without
+// converting it, in which case the MQMD describes the message as it was sent.
+func isConversionWarning(mqret *ibmmq.MQReturn) bool {
+
+	if mqret.MQCC != ibmmq.MQCC_WARNING {
+		return false
+	}
+
+	switch mqret.MQRC {
+	case ibmmq.MQRC_NOT_CONVERTED, ibmmq.MQRC_FORMAT_ERROR, ibmmq.MQRC_CONVERTED_MSG_TOO_BIG,
+		ibmmq.MQRC_SOURCE_CCSID_ERROR, ibmmq.MQRC_TARGET_CCSID_ERROR,
+		ibmmq.MQRC_SOURCE_INTEGER_ENC_ERROR, ibmmq.MQRC_TARGET_INTEGER_ENC_ERROR,
+		ibmmq.MQRC_DBCS_ERROR:
+		return true
+	}
+
+	return false
+}
+
+// isUTF16BigEndian returns the byte order of UTF-16 data in the specified CCSID,
+// which for CCSIDs that don't specify the byte order is taken from the integer
+// encoding of the message.
+func isUTF16BigEndian(ccsid int32, encoding int32) bool {
+
+	switch ccsid {
+	case ccsidUTF16BE:
+		return true
+	case ccsidUTF16LE:
+		return false
+	}
+
+	return encoding&ibmmq.MQENC_INTEGER_MASK != ibmmq.MQENC_INTEGER_REVERSED
+}
+
+// isASCII returns true if the string only contains 7-bit ASCII characters.
+func isASCII(text string) bool {
+
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// JSONCodec is a Codec that encodes values as JSON text.
+type JSONCodec struct{}
+
+// JSONCodec_NAME is the name that is recorded on messages encoded by the JSONCodec.
+const JSONCodec_NAME string = "json"
+
+// GetName returns the name of the JSON codec.
+func (codec JSONCodec) GetName() string {
+	return JSONCodec_NAME
+}
+
+// GetFormat returns the MQ string format, as JSON is a text encoding.
+func (codec JSONCodec) GetFormat() string {
+	return ibmmq.MQFMT_STRING
+}
+
+// Marshal encodes the value as JSON.
+func (codec JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON into the value pointed to by v.
+func (codec JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// codecRegistry holds the codecs that can be selected by name when decoding the
+// body of a received message.
+var codecRegistry = map[string]jms20subset.Codec{
+	JSONCodec_NAME: JSONCodec{},
+}
+
+// codecRegistryLock synchronizes access to the codecRegistry.
+var codecRegistryLock sync.RWMutex
+
+// RegisterCodec makes a codec available to GetBody and ReceiveBody, which use
+// it to decode messages that were created by CreateMessageFromValue with a codec
+// of the same name. Registering a codec replaces any existing codec of that name.
+//
+// The JSONCodec is registered automatically.
+func RegisterCodec(codec jms20subset.Codec) {
+
+	codecRegistryLock.Lock()
+	defer codecRegistryLock.Unlock()
+
+	codecRegistry[codec.GetName()] = codec
+}
+
+// lookupCodec returns the registered codec with the specified name.
+func lookupCodec(name string) (jms20subset.Codec, bool) {
+
+	codecRegistryLock.RLock()
+	defer codecRegistryLock.RUnlock()
+
+	codec, found := codecRegistry[name]
+	return codec, found
+}
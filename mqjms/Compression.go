@@ -0,0 +1,160 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// MessageImpl_PROPERTY_COMPRESSION is the name of the message property that marks
+// a message whose body has been compressed, and records the algorithm that was used.
+const MessageImpl_PROPERTY_COMPRESSION string = "mqjms_Compression"
+
+// defaultMaxDecompressedSize is the largest size that a message body is decompressed
+// to if ConnectionFactoryImpl.MaxDecompressedSize is not set, which is the maximum
+// length of an MQ message.
+const defaultMaxDecompressedSize = 100 * 1024 * 1024
+
+// MessageImpl_PROPERTY_ORIGINAL_FORMAT is the name of the message property that
+// records the MQ format of a message before its body was compressed.
+const MessageImpl_PROPERTY_ORIGINAL_FORMAT string = "mqjms_OriginalFormat"
+
+// compressBody compresses the body of a message using the specified algorithm.
+func compressBody(algorithm string, body []byte) ([]byte, error) {
+
+	switch algorithm {
+	case jms20subset.Compression_GZIP:
+
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+
+		_, err := writer.Write(body)
+		if err == nil {
+			err = writer.Close()
+		}
+
+		return compressed.Bytes(), err
+
+	default:
+		return nil, errors.New("Unsupported compression algorithm: " + algorithm)
+	}
+}
+
+// decompressBody reverses the compression of a message body, failing if the
+// decompressed body would be larger than maxLength bytes so that a small message
+// cannot expand to exhaust the memory of the application.
+func decompressBody(algorithm string, body []byte, maxLength int) ([]byte, error) {
+
+	switch algorithm {
+	case jms20subset.Compression_GZIP:
+
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		// Read one byte more than the limit to find out whether it was exceeded.
+		decompressed, err := io.ReadAll(io.LimitReader(reader, int64(maxLength)+1))
+		if err == nil && len(decompressed) > maxLength {
+			err = errors.New("Decompressed body is larger than the maximum of " + strconv.Itoa(maxLength) + " bytes")
+		}
+
+		return decompressed, err
+
+	default:
+		return nil, errors.New("Unsupported compression algorithm: " + algorithm)
+	}
+}
+
+// markCompressed records the compression on the message so that the consumer can
+// reverse it, and sets the format to indicate that the body is binary data so that
+// the queue manager does not attempt to convert it.
+//
+// The caller must hold the context lock.
+func markCompressed(msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, algorithm string) error {
+
+	smpo := ibmmq.NewMQSMPO()
+	pd := ibmmq.NewMQPD()
+
+	err := msgHandle.SetMP(smpo, MessageImpl_PROPERTY_COMPRESSION, pd, algorithm)
+	if err == nil {
+		err = msgHandle.SetMP(smpo, MessageImpl_PROPERTY_ORIGINAL_FORMAT, pd, mqmd.Format)
+	}
+
+	mqmd.Format = ibmmq.MQFMT_NONE
+
+	return err
+}
+
+// unmarkCompressed removes the compression marker from the message and restores
+// its original format. A marker that isn't present is not an error, as the message
+// might only have been partly marked. The caller must hold the context lock.
+func unmarkCompressed(msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, originalFormat string) error {
+
+	dmpo := ibmmq.NewMQDMPO()
+
+	mqmd.Format = originalFormat
+
+	for _, name := range []string{MessageImpl_PROPERTY_COMPRESSION, MessageImpl_PROPERTY_ORIGINAL_FORMAT} {
+
+		err := msgHandle.DltMP(dmpo, name)
+		if err != nil && err.(*ibmmq.MQReturn).MQRC != ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decompressMessage checks whether a message that has been received is marked as
+// compressed, and if so returns the decompressed body and restores the original
+// format and properties of the message. Bodies that are not compressed are
+// returned unchanged. The decompressed body can be no larger than maxLength bytes.
+//
+// The caller must hold the context lock.
+func decompressMessage(msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, body []byte, maxLength int) ([]byte, error) {
+
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+
+	_, value, err := msgHandle.InqMP(impo, pd, MessageImpl_PROPERTY_COMPRESSION)
+	if err != nil {
+		// The marker is not present, so the body is not compressed.
+		return body, nil
+	}
+
+	algorithm, _ := value.(string)
+
+	decompressed, err := decompressBody(algorithm, body, maxLength)
+	if err != nil {
+		return nil, err
+	}
+
+	originalFormat := ibmmq.MQFMT_NONE
+	_, value, err = msgHandle.InqMP(impo, pd, MessageImpl_PROPERTY_ORIGINAL_FORMAT)
+	if err == nil {
+		originalFormat, _ = value.(string)
+	}
+
+	err = unmarkCompressed(msgHandle, mqmd, originalFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	return decompressed, nil
+}
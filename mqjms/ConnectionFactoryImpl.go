@@ -48,6 +48,11 @@ type ConnectionFactoryImpl struct {
 	// Controls the size of the buffer used when receiving a message (default is 32kb if not set)
 	ReceiveBufferSize int
 
+	// Controls the largest size that the body of a compressed message can be decompressed
+	// to when it is received, so that a small message can't expand to exhaust the memory
+	// of the application (default is 100MB, the maximum length of an MQ message, if not set)
+	MaxDecompressedSize int
+
 	// SetCheckCount defines the number of messages that will be asynchronously put using
 	// this Context between checks for errors. For example a value of 10 will cause an error
 	// check to be triggered once for every 10 messages.
@@ -56,6 +61,11 @@ type ConnectionFactoryImpl struct {
 	//
 	// Default of 0 (zero) means that no checks are made for asynchronous put calls.
 	SendCheckCount int
+
+	// ProtectionPolicies defines how messages are signed and/or encrypted when they are
+	// sent to, and verified and/or decrypted when they are received from, each named
+	// destination. Destinations that are not listed are not protected.
+	ProtectionPolicies map[string]ProtectionPolicy
 }
 
 // CreateContext implements the JMS method to create a connection to an IBM MQ
@@ -155,12 +165,14 @@ func (cf ConnectionFactoryImpl) CreateContextWithSessionMode(sessionMode int, mq
 		// Connection was created successfully, so we wrap the MQI object into
 		// a new ContextImpl and return it to the caller.
 		ctx = ContextImpl{
-			qMgr:              qMgr,
-			ctxLock:           &sync.Mutex{},
-			sessionMode:       sessionMode,
-			receiveBufferSize: cf.ReceiveBufferSize,
-			sendCheckCount:    cf.SendCheckCount,
-			sendCheckCountInc: countInc,
+			qMgr:                qMgr,
+			ctxLock:             &sync.Mutex{},
+			sessionMode:         sessionMode,
+			receiveBufferSize:   cf.ReceiveBufferSize,
+			maxDecompressedSize: cf.MaxDecompressedSize,
+			sendCheckCount:      cf.SendCheckCount,
+			sendCheckCountInc:   countInc,
+			protectionPolicies:  cf.ProtectionPolicies,
 		}
 
 	}
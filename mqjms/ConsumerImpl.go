@@ -10,12 +10,12 @@
 package mqjms
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"runtime"
+	"io"
+	"iter"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
 	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
@@ -24,14 +24,20 @@ import (
 // ConsumerImpl defines a struct that contains the necessary objects for
 // receiving messages from a queue on an IBM MQ queue manager.
 type ConsumerImpl struct {
-	ctx      ContextImpl
-	qObject  ibmmq.MQObject
-	selector string
+	ctx        ContextImpl
+	qObject    ibmmq.MQObject
+	dest       jms20subset.Destination
+	selector   string
+	groupOrder bool // Receive only complete message groups, in logical order
 }
 
 // ReceiveNoWait implements the IBM MQ logic necessary to receive a message from
 // a Destination, or immediately return a nil Message if there is no available
 // message to be received.
+//
+// If a message is received but cannot be decompressed, or its text cannot be
+// converted from its character set, then it is returned as a BytesMessage
+// containing the body exactly as it was received, along with the error.
 func (consumer ConsumerImpl) ReceiveNoWait() (jms20subset.Message, jms20subset.JMSException) {
 
 	gmo := ibmmq.NewMQGMO()
@@ -42,6 +48,9 @@ func (consumer ConsumerImpl) ReceiveNoWait() (jms20subset.Message, jms20subset.J
 // Receive with waitMillis returns a message if one is available, or otherwise
 // waits for up to the specified number of milliseconds for one to become
 // available. A value of zero or less indicates to wait indefinitely.
+//
+// As with ReceiveNoWait, a message that cannot be decompressed or converted is
+// returned along with the error.
 func (consumer ConsumerImpl) Receive(waitMillis int32) (jms20subset.Message, jms20subset.JMSException) {
 
 	if waitMillis <= 0 {
@@ -56,9 +65,94 @@ func (consumer ConsumerImpl) Receive(waitMillis int32) (jms20subset.Message, jms
 
 }
 
+// ReceiveStream returns a reader for the body of the next message, which is
+// received one segment at a time so that the whole of the body never needs to be
+// held in memory. Messages that are not segmented are returned as a single segment.
+//
+// The segments are received under syncpoint. If this context is not transacted
+// then they are committed when the end of the stream is reached, or backed out if
+// the reader is closed before then so that the message can be received again.
+// Otherwise they are committed or backed out along with the rest of the transaction.
+//
+// The selector of this consumer is applied to the first segment only. Streams
+// cannot be protected, so an exception is returned if a protection policy applies
+// to the destination of this consumer.
+func (consumer ConsumerImpl) ReceiveStream(waitMillis int32) (io.ReadCloser, jms20subset.JMSException) {
+
+	protectionErr := checkStreamProtection(consumer.ctx, consumer.dest)
+	if protectionErr != nil {
+		return nil, protectionErr
+	}
+
+	if waitMillis <= 0 {
+		waitMillis = ibmmq.MQWI_UNLIMITED
+	}
+
+	gmo := ibmmq.NewMQGMO()
+	gmo.Options |= ibmmq.MQGMO_WAIT
+	gmo.WaitInterval = waitMillis
+
+	reader := &streamReaderImpl{
+		consumer: consumer,
+	}
+
+	jmsErr := reader.getSegment(gmo, consumer.selector)
+	if jmsErr != nil || reader.segment == nil {
+		return nil, jmsErr
+	}
+
+	return reader, nil
+}
+
+// Messages returns a sequence of the messages received by this consumer, for
+// use in a "for msg, err := range consumer.Messages(...)" loop.
+//
+// Each receive waits for up to waitMillis for a message to arrive, after which
+// the ctx is checked again, so the wait also controls how quickly the sequence
+// notices that the ctx is done. A value of zero or less indicates not to wait,
+// so the sequence ends as soon as there is no message immediately available.
+//
+// If an error occurs it is returned as the final element of the sequence.
+func (consumer ConsumerImpl) Messages(ctx context.Context, waitMillis int32) iter.Seq2[jms20subset.Message, error] {
+
+	return func(yield func(jms20subset.Message, error) bool) {
+
+		for ctx.Err() == nil {
+
+			var msg jms20subset.Message
+			var jmsErr jms20subset.JMSException
+
+			if waitMillis > 0 {
+				msg, jmsErr = consumer.Receive(waitMillis)
+			} else {
+				msg, jmsErr = consumer.ReceiveNoWait()
+			}
+
+			if jmsErr != nil {
+				// The message is also returned if it was received but couldn't be processed.
+				yield(msg, jmsErr)
+				return
+			}
+
+			if msg == nil {
+				if waitMillis > 0 {
+					// No message arrived during the wait, so check the ctx and
+					// try again.
+					continue
+				}
+				return
+			}
+
+			if !yield(msg, nil) {
+				return
+			}
+		}
+	}
+}
+
 // Internal method to provide common functionality across the different types
 // of receive.
-func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Message, jms20subset.JMSException) {
+func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (msg jms20subset.Message, jmsErr jms20subset.JMSException) {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use (below) to delete unused MessageHandles.
@@ -66,9 +160,6 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 	defer consumer.ctx.ctxLock.Unlock()
 
 	// Prepare objects to be used in receiving the message.
-	var msg jms20subset.Message
-	var jmsErr jms20subset.JMSException
-
 	getmqmd := ibmmq.NewMQMD()
 
 	myBufferSize := 32768
@@ -79,16 +170,60 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 
 	buffer := make([]byte, myBufferSize)
 
-	// Calculate the syncpoint value
+	// Calculate the syncpoint value, which the caller can also request if it needs
+	// to be able to back out the receive. Browsing a message is never done under
+	// syncpoint, as MQ doesn't allow the two options to be combined.
+	browseOptions := ibmmq.MQGMO_BROWSE_FIRST | ibmmq.MQGMO_BROWSE_NEXT | ibmmq.MQGMO_BROWSE_MSG_UNDER_CURSOR
 	syncpointSetting := ibmmq.MQGMO_NO_SYNCPOINT
-	if consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+	if (consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED || gmo.Options&ibmmq.MQGMO_SYNCPOINT != 0) &&
+		gmo.Options&browseOptions == 0 {
 		syncpointSetting = ibmmq.MQGMO_SYNCPOINT
 	}
 
+	// Messages that are protected by the producer are verified and/or decrypted
+	// according to the policy for the destination.
+	var policy *ProtectionPolicy
+	if destPolicy, ok := consumer.ctx.protectionPolicies[consumer.dest.GetDestinationName()]; ok &&
+		destPolicy.QualityOfProtection != ProtectionPolicy_QOP_NONE {
+		policy = &destPolicy
+	}
+
+	// A protected message is received in its own unit of work if it isn't already
+	// under syncpoint, so that if it fails verification it can be moved to the error
+	// queue without the possibility of it being lost.
+	backout := false
+	if policy != nil && syncpointSetting == ibmmq.MQGMO_NO_SYNCPOINT && gmo.Options&browseOptions == 0 {
+		syncpointSetting = ibmmq.MQGMO_SYNCPOINT
+
+		defer func() {
+			if backout {
+				consumer.ctx.qMgr.Back()
+				return
+			}
+
+			cmitErr := consumer.ctx.qMgr.Cmit()
+			if cmitErr != nil {
+				rcInt := int(cmitErr.(*ibmmq.MQReturn).MQRC)
+				errCode := strconv.Itoa(rcInt)
+				reason := ibmmq.MQItoString("RC", rcInt)
+				msg = nil
+				jmsErr = jms20subset.CreateJMSException(reason, errCode, cmitErr)
+			}
+		}()
+	}
+
 	// Set the GMO (get message options)
+	gmo.Options &^= ibmmq.MQGMO_SYNCPOINT
 	gmo.Options |= syncpointSetting
 	gmo.Options |= ibmmq.MQGMO_FAIL_IF_QUIESCING
 
+	// Only receive complete message groups, in the order that they were sent.
+	// The group details are returned in version 2 of the MQMD.
+	if consumer.groupOrder {
+		getmqmd.Version = ibmmq.MQMD_VERSION_2
+		gmo.Options |= ibmmq.MQGMO_LOGICAL_ORDER | ibmmq.MQGMO_ALL_MSGS_AVAILABLE
+	}
+
 	// Include the message properties in the msgHandle
 	gmo.Options |= ibmmq.MQGMO_PROPERTIES_IN_HANDLE
 	cmho := ibmmq.NewMQCMHO()
@@ -102,34 +237,120 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 		return nil, jmsErr
 	}
 
+	// Ask the queue manager to convert the message into the receive CCSID if the
+	// destination requests it, otherwise text messages are converted below.
+	convert := false
+	if queue, ok := consumer.dest.(jms20subset.Queue); ok &&
+		queue.GetReceiveConversion() == jms20subset.Destination_RECEIVE_CONVERSION_QMGR {
+
+		gmo.Options |= ibmmq.MQGMO_CONVERT
+		getmqmd.CodedCharSetId = int32(queue.GetReceiveCCSID())
+		getmqmd.Encoding = ibmmq.MQENC_NATIVE
+		convert = true
+	}
+
 	// Use the prepared objects to ask for a message from the queue.
 	datalen, err := consumer.qObject.Get(getmqmd, gmo, buffer)
 
+	// A message that the queue manager is unable to convert is still received,
+	// along with a warning, so carry on as normal.
+	if err != nil && convert && isConversionWarning(err.(*ibmmq.MQReturn)) {
+		err = nil
+	}
+
 	if err == nil {
 
-		// Set a finalizer on the message handle to allow it to be deleted
-		// when it is no longer referenced by an active object, to reduce/prevent
-		// memory leaks.
-		setMessageHandlerFinalizer(thisMsgHandle, consumer.ctx.ctxLock)
+		// Track the message handle so that it is deleted when the message is
+		// closed, or when it is no longer referenced by an active object.
+		handleTracker := newMessageHandleTracker(&thisMsgHandle, consumer.ctx.ctxLock)
+
+		// Keep the message descriptor as it was received in case the message needs
+		// to be moved to the error queue.
+		receivedMQMD := *getmqmd
+
+		// Messages on a dead-letter queue start with an MQDLH that describes why the
+		// message could not be delivered, and the format of the original message.
+		dlh, body := stripDeadLetterHeader(getmqmd, buffer[:datalen])
+
+		// Verify and/or decrypt the message if it was protected by the producer,
+		// which restores the original format and properties of the message.
+		unprotectedBody, unprotectErr := unprotectMessage(policy, &thisMsgHandle, getmqmd, body)
+		if unprotectErr != nil {
+
+			// A message that has been browsed is still on the queue. Otherwise move it
+			// to the error queue in the same unit of work that it was received. The
+			// error code is only UnprotectFailed if the message was moved, so that the
+			// caller knows whether it is safe to commit.
+			errCode := "UnprotectFailed"
+			if policy != nil && gmo.Options&browseOptions == 0 {
+
+				moveErr := consumer.moveToErrorQueue(policy, &receivedMQMD, &thisMsgHandle, buffer[:datalen])
+				if moveErr != nil {
+					backout = true
+					errCode = strconv.Itoa(int(moveErr.(*ibmmq.MQReturn).MQRC))
+					unprotectErr = errors.New(unprotectErr.Error() + ", and the message could not be moved to the error queue: " + moveErr.Error())
+				}
+			}
+
+			jmsErr = jms20subset.CreateJMSException("UnprotectFailed", errCode, unprotectErr)
+			return nil, jmsErr
+		}
+		body = unprotectedBody
+
+		// Reverse any compression that was applied by the producer, which also
+		// restores the original format of the message.
+		maxDecompressedSize := defaultMaxDecompressedSize
+		if consumer.ctx.maxDecompressedSize > 0 {
+			maxDecompressedSize = consumer.ctx.maxDecompressedSize
+		}
+
+		receivedFormat := getmqmd.Format
+		decompressedBody, decompressErr := decompressMessage(&thisMsgHandle, getmqmd, body, maxDecompressedSize)
+		if decompressErr != nil {
+
+			// The message has been removed from the queue, so return it as it was
+			// received rather than discarding it.
+			getmqmd.Format = receivedFormat
+			msg = consumer.createRawMessage(getmqmd, &thisMsgHandle, handleTracker, dlh, body)
+			jmsErr = jms20subset.CreateJMSException("DecompressionFailed", "DecompressionFailed", decompressErr)
+			return msg, jmsErr
+		}
+		body = decompressedBody
+		buffer = body
+		datalen = len(body)
 
 		// Message received successfully (without error).
 		// Determine on the basis of the format field what sort of message to create.
 
-		if getmqmd.Format == ibmmq.MQFMT_STRING {
+		if getmqmd.Format == ibmmq.MQFMT_STRING && getmqmd.MsgType != ibmmq.MQMT_REPORT {
 
 			var msgBodyStr *string
 
 			if datalen > 0 {
-				strContent := string(buffer[:datalen])
+
+				// Convert the text from the character set of the message.
+				strContent, decodeErr := decodeText(buffer[:datalen], getmqmd.CodedCharSetId, getmqmd.Encoding)
+				if decodeErr != nil {
+
+					// The message has been removed from the queue, so return its body
+					// as bytes rather than discarding it.
+					msg = consumer.createRawMessage(getmqmd, &thisMsgHandle, handleTracker, dlh, buffer[:datalen])
+					jmsErr = jms20subset.CreateJMSException("CharacterSetConversionFailed",
+						"CharacterSetConversionFailed", decodeErr)
+					return msg, jmsErr
+				}
 				msgBodyStr = &strContent
 			}
 
 			msg = &TextMessageImpl{
 				bodyStr: msgBodyStr,
 				MessageImpl: MessageImpl{
-					mqmd:      getmqmd,
-					msgHandle: &thisMsgHandle,
-					ctxLock:   consumer.ctx.ctxLock,
+					mqmd:          getmqmd,
+					msgHandle:     &thisMsgHandle,
+					handleTracker: handleTracker,
+					ctxLock:       consumer.ctx.ctxLock,
+					destination:   consumer.dest,
+					dlh:           dlh,
 				},
 			}
 
@@ -142,14 +363,25 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 			trimmedBuffer := buffer[0:datalen]
 
 			// Not a string, so fall back to BytesMessage
-			msg = &BytesMessageImpl{
+			bytesMsg := BytesMessageImpl{
 				bodyBytes: &trimmedBuffer,
 				MessageImpl: MessageImpl{
-					mqmd:      getmqmd,
-					msgHandle: &thisMsgHandle,
-					ctxLock:   consumer.ctx.ctxLock,
+					mqmd:          getmqmd,
+					msgHandle:     &thisMsgHandle,
+					handleTracker: handleTracker,
+					ctxLock:       consumer.ctx.ctxLock,
+					destination:   consumer.dest,
+					dlh:           dlh,
 				},
 			}
+
+			// Report messages contain whatever data was requested from the original
+			// message, whatever its format.
+			if getmqmd.MsgType == ibmmq.MQMT_REPORT {
+				msg = &ReportMessageImpl{BytesMessageImpl: bytesMsg}
+			} else {
+				msg = &bytesMsg
+			}
 		}
 
 	} else {
@@ -184,34 +416,22 @@ func (consumer ConsumerImpl) receiveInternal(gmo *ibmmq.MQGMO) (jms20subset.Mess
 	return msg, jmsErr
 }
 
-/*
- * Set a finalizer on the message handle to allow it to be deleted
- * when it is no longer referenced by an active object, to reduce/prevent
- * memory leaks.
- */
-func setMessageHandlerFinalizer(thisMsgHandle ibmmq.MQMessageHandle, ctxLock *sync.Mutex) {
-
-	runtime.SetFinalizer(&thisMsgHandle, func(msgHandle *ibmmq.MQMessageHandle) {
-		ctxLock.Lock()
-		defer ctxLock.Unlock()
-
-		dmho := ibmmq.NewMQDMHO()
-		err := msgHandle.DltMH(dmho)
-		if err != nil {
-
-			mqret := err.(*ibmmq.MQReturn)
-
-			if mqret.MQRC == ibmmq.MQRC_HCONN_ERROR {
-				// Expected if the connection is closed before the finalizer executes
-				// (at which point it should get tidied up automatically by the connection)
-			} else {
-				fmt.Println("DltMH finalizer", err)
-			}
-
-		}
-
-	})
-
+// createRawMessage returns a BytesMessage containing the body of a received message
+// exactly as it was received, for a message that could not be processed.
+func (consumer ConsumerImpl) createRawMessage(getmqmd *ibmmq.MQMD, msgHandle *ibmmq.MQMessageHandle,
+	handleTracker *messageHandleTracker, dlh *ibmmq.MQDLH, body []byte) jms20subset.Message {
+
+	return &BytesMessageImpl{
+		bodyBytes: &body,
+		MessageImpl: MessageImpl{
+			mqmd:          getmqmd,
+			msgHandle:     msgHandle,
+			handleTracker: handleTracker,
+			ctxLock:       consumer.ctx.ctxLock,
+			destination:   consumer.dest,
+			dlh:           dlh,
+		},
+	}
 }
 
 // ReceiveStringBodyNoWait implements the IBM MQ logic necessary to receive a
@@ -237,6 +457,10 @@ func (consumer ConsumerImpl) ReceiveStringBodyNoWait() (*string, jms20subset.JMS
 				"MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
 		}
 
+		// Only the body is returned to the application, so the message handle
+		// can be deleted straight away rather than waiting for the finalizer.
+		msg.Close()
+
 	}
 
 	return msgBodyStrPtr, jmsErr
@@ -267,6 +491,10 @@ func (consumer ConsumerImpl) ReceiveStringBody(waitMillis int32) (*string, jms20
 				"MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
 		}
 
+		// Only the body is returned to the application, so the message handle
+		// can be deleted straight away rather than waiting for the finalizer.
+		msg.Close()
+
 	}
 
 	return msgBodyStrPtr, jmsErr
@@ -296,6 +524,10 @@ func (consumer ConsumerImpl) ReceiveBytesBodyNoWait() (*[]byte, jms20subset.JMSE
 				"MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
 		}
 
+		// Only the body is returned to the application, so the message handle
+		// can be deleted straight away rather than waiting for the finalizer.
+		msg.Close()
+
 	}
 
 	return msgBodyPtr, jmsErr
@@ -326,6 +558,10 @@ func (consumer ConsumerImpl) ReceiveBytesBody(waitMillis int32) (*[]byte, jms20s
 				"MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
 		}
 
+		// Only the body is returned to the application, so the message handle
+		// can be deleted straight away rather than waiting for the finalizer.
+		msg.Close()
+
 	}
 
 	return msgBodyPtr, jmsErr
@@ -0,0 +1,524 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// MessageHandler is the function that a ConsumerPool calls for each message
+// that it receives.
+//
+// When the pool uses a transacted session the receive of the message is committed
+// if the handler returns nil, and rolled back (so that the message is delivered
+// again) if the handler returns an error. A handler that panics is treated in the
+// same way as one that returns an error.
+type MessageHandler func(msg jms20subset.Message) error
+
+// ConsumerPool runs a number of consumers against a single queue, each with its
+// own connection to the queue manager and its own goroutine, and passes every
+// message that they receive to a MessageHandler.
+type ConsumerPool struct {
+	cf      ConnectionFactoryImpl
+	dest    jms20subset.Destination
+	handler MessageHandler
+
+	sessionMode       int
+	receiveWaitMillis int32
+	exceptionListener func(jms20subset.JMSException)
+
+	// Attributes that control dynamic scaling, which is disabled if maxConcurrency is zero.
+	minConcurrency      int
+	maxConcurrency      int
+	messagesPerConsumer int
+	scaleInterval       time.Duration
+
+	poolLock    *sync.Mutex // Mutex to synchronize changes to the set of workers
+	concurrency int
+	workers     []*consumerPoolWorker
+	workerGroup *sync.WaitGroup
+	scaleStop   chan struct{}
+	running     bool
+}
+
+// consumerPoolWorker holds the objects used by one of the goroutines in the pool.
+type consumerPoolWorker struct {
+	context  jms20subset.JMSContext
+	consumer jms20subset.JMSConsumer
+	stop     chan struct{}
+
+	// Backout attributes of the queue, used to remove poison messages when the
+	// pool is transacted. Disabled if backoutThreshold is zero.
+	backoutThreshold int
+	backoutQueue     jms20subset.Queue
+}
+
+// ConsumerPool_DEFAULT_RECEIVE_WAIT is the default number of milliseconds that each
+// consumer waits for a message, which is also the longest time it takes a consumer
+// to notice that the pool is being stopped.
+const ConsumerPool_DEFAULT_RECEIVE_WAIT int32 = 1000
+
+// NewConsumerPool creates a pool of concurrency consumers that receive messages
+// from the specified Destination and pass them to the handler.
+//
+// By default the consumers use an auto-acknowledge session. The pool does not
+// connect to the queue manager until Start is called, which returns an error if
+// the concurrency is negative.
+func NewConsumerPool(cf ConnectionFactoryImpl, dest jms20subset.Destination, concurrency int, handler MessageHandler) *ConsumerPool {
+
+	return &ConsumerPool{
+		cf:                cf,
+		dest:              dest,
+		handler:           handler,
+		sessionMode:       jms20subset.JMSContextAUTOACKNOWLEDGE,
+		receiveWaitMillis: ConsumerPool_DEFAULT_RECEIVE_WAIT,
+		poolLock:          &sync.Mutex{},
+		concurrency:       concurrency,
+		workerGroup:       &sync.WaitGroup{},
+	}
+}
+
+// SetSessionMode sets the session mode used by each consumer in the pool, for
+// example jms20subset.JMSContextSESSIONTRANSACTED so that each message is received
+// under its own transaction. Must be called before Start.
+//
+// In a transacted pool a message that has been backed out as many times as the
+// backout threshold (BOTHRESH) of the queue is moved to its backout requeue queue
+// (BOQNAME) instead of being passed to the handler again. If the queue doesn't
+// have a BOQNAME then the message is reported to the exception listener each
+// time it is delivered.
+func (pool *ConsumerPool) SetSessionMode(sessionMode int) *ConsumerPool {
+	pool.sessionMode = sessionMode
+	return pool
+}
+
+// SetReceiveWait sets the number of milliseconds that each consumer waits for a
+// message before checking whether the pool has been asked to stop. Must be called
+// before Start.
+func (pool *ConsumerPool) SetReceiveWait(waitMillis int32) *ConsumerPool {
+
+	// Only accept a positive value, as waiting indefinitely would prevent the pool
+	// from being stopped.
+	if waitMillis > 0 {
+		pool.receiveWaitMillis = waitMillis
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid ReceiveWait specified: " + strconv.Itoa(int(waitMillis)))
+	}
+
+	return pool
+}
+
+// SetExceptionListener registers a function that is called when a consumer in the
+// pool encounters an error, or when the handler returns an error for a message.
+// If no listener is set then the errors are printed to the console.
+func (pool *ConsumerPool) SetExceptionListener(listener func(jms20subset.JMSException)) *ConsumerPool {
+	pool.exceptionListener = listener
+	return pool
+}
+
+// SetAutoScaling enables the pool to scale the number of consumers up and down
+// between minConcurrency and maxConcurrency, checking the depth of the queue at
+// the specified interval and running one consumer for every messagesPerConsumer
+// messages on the queue. Must be called before Start.
+func (pool *ConsumerPool) SetAutoScaling(minConcurrency int, maxConcurrency int, messagesPerConsumer int, interval time.Duration) *ConsumerPool {
+
+	if minConcurrency >= 0 && maxConcurrency >= minConcurrency && maxConcurrency > 0 &&
+		messagesPerConsumer > 0 && interval > 0 {
+
+		pool.minConcurrency = minConcurrency
+		pool.maxConcurrency = maxConcurrency
+		pool.messagesPerConsumer = messagesPerConsumer
+		pool.scaleInterval = interval
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid AutoScaling specified: min=" + strconv.Itoa(minConcurrency) +
+			", max=" + strconv.Itoa(maxConcurrency) + ", messagesPerConsumer=" + strconv.Itoa(messagesPerConsumer))
+	}
+
+	return pool
+}
+
+// Start connects the consumers to the queue manager and begins receiving messages.
+//
+// If any of the consumers cannot be created then the ones that were created are
+// closed again, and the error is returned.
+func (pool *ConsumerPool) Start() jms20subset.JMSException {
+
+	pool.poolLock.Lock()
+	defer pool.poolLock.Unlock()
+
+	if pool.running {
+		return nil
+	}
+
+	if pool.concurrency < 0 {
+		return createInvalidConcurrencyException(pool.concurrency)
+	}
+
+	retErr := pool.scaleToInternal(pool.concurrency)
+	if retErr != nil {
+		pool.stopWorkers(pool.workers)
+		pool.workers = nil
+		return retErr
+	}
+
+	pool.running = true
+
+	if pool.maxConcurrency > 0 {
+		pool.scaleStop = make(chan struct{})
+		go pool.autoScale(pool.scaleStop)
+	}
+
+	return nil
+}
+
+// ScaleTo changes the number of consumers in the pool. Consumers that are removed
+// finish processing their current message before they are closed.
+func (pool *ConsumerPool) ScaleTo(concurrency int) jms20subset.JMSException {
+
+	if concurrency < 0 {
+		return createInvalidConcurrencyException(concurrency)
+	}
+
+	pool.poolLock.Lock()
+	defer pool.poolLock.Unlock()
+
+	pool.concurrency = concurrency
+
+	if !pool.running {
+		// Applied when the pool is started.
+		return nil
+	}
+
+	return pool.scaleToInternal(concurrency)
+}
+
+// GetConcurrency returns the number of consumers that are currently running.
+func (pool *ConsumerPool) GetConcurrency() int {
+
+	pool.poolLock.Lock()
+	defer pool.poolLock.Unlock()
+
+	return len(pool.workers)
+}
+
+// Stop gracefully shuts down the pool, waiting for each consumer to finish
+// processing its current message before closing it.
+func (pool *ConsumerPool) Stop() {
+
+	pool.poolLock.Lock()
+
+	if pool.scaleStop != nil {
+		close(pool.scaleStop)
+		pool.scaleStop = nil
+	}
+
+	workers := pool.workers
+	pool.workers = nil
+	pool.running = false
+
+	pool.poolLock.Unlock()
+
+	pool.stopWorkers(workers)
+	pool.workerGroup.Wait()
+}
+
+// scaleToInternal starts or stops workers to reach the requested concurrency.
+// The caller must hold the poolLock.
+func (pool *ConsumerPool) scaleToInternal(concurrency int) jms20subset.JMSException {
+
+	for len(pool.workers) < concurrency {
+
+		worker, retErr := pool.createWorker()
+		if retErr != nil {
+			return retErr
+		}
+
+		pool.workers = append(pool.workers, worker)
+		pool.workerGroup.Add(1)
+		go pool.runWorker(worker)
+	}
+
+	if len(pool.workers) > concurrency {
+		pool.stopWorkers(pool.workers[concurrency:])
+		pool.workers = pool.workers[:concurrency]
+	}
+
+	return nil
+}
+
+// createWorker creates the connection and consumer used by one of the goroutines.
+func (pool *ConsumerPool) createWorker() (*consumerPoolWorker, jms20subset.JMSException) {
+
+	context, retErr := pool.cf.CreateContextWithSessionMode(pool.sessionMode)
+	if retErr != nil {
+		if context != nil {
+			context.Close()
+		}
+		return nil, retErr
+	}
+
+	consumer, retErr := context.CreateConsumer(pool.dest)
+	if retErr != nil {
+		context.Close()
+		return nil, retErr
+	}
+
+	worker := &consumerPoolWorker{
+		context:  context,
+		consumer: consumer,
+		stop:     make(chan struct{}),
+	}
+
+	// Look up where poison messages should be moved to. The pool carries on without
+	// removing them if the queue can't be inquired, for example because the
+	// application isn't authorized to do so.
+	if pool.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+
+		attrs, inqErr := context.InquireQueue(pool.dest)
+		if inqErr != nil {
+			pool.reportException(inqErr)
+
+		} else if attrs.BackoutThreshold > 0 {
+			worker.backoutThreshold = attrs.BackoutThreshold
+			if attrs.BackoutRequeueQName != "" {
+				worker.backoutQueue = context.CreateQueue(attrs.BackoutRequeueQName)
+			}
+		}
+	}
+
+	return worker, nil
+}
+
+// stopWorkers tells the specified workers to stop once they have finished processing
+// their current message. The worker goroutines close their own consumer and context.
+func (pool *ConsumerPool) stopWorkers(workers []*consumerPoolWorker) {
+
+	for _, worker := range workers {
+		close(worker.stop)
+	}
+}
+
+// runWorker is the receive loop that is run by each goroutine in the pool.
+func (pool *ConsumerPool) runWorker(worker *consumerPoolWorker) {
+
+	defer pool.workerGroup.Done()
+	defer worker.context.Close()
+	defer worker.consumer.Close()
+
+	for {
+
+		// Check whether we have been asked to stop before waiting for another message.
+		select {
+		case <-worker.stop:
+			return
+		default:
+		}
+
+		msg, retErr := worker.consumer.Receive(pool.receiveWaitMillis)
+
+		// A message that has been backed out too many times is moved to the backout
+		// queue rather than being passed to the handler again.
+		poison := retErr == nil && msg != nil &&
+			worker.backoutThreshold > 0 && getBackoutCount(msg) >= worker.backoutThreshold
+		if poison {
+			retErr = pool.backoutMessage(worker, msg)
+		}
+
+		if retErr != nil {
+			pool.reportException(retErr)
+
+			// Don't spin on a failing connection (or a poison message that can't be
+			// moved); wait before trying again unless we are asked to stop in the meantime.
+			select {
+			case <-worker.stop:
+				return
+			case <-time.After(time.Duration(pool.receiveWaitMillis) * time.Millisecond):
+			}
+			continue
+		}
+
+		if msg == nil || poison {
+			// No message arrived within the wait interval, or it has been moved to
+			// the backout queue.
+			continue
+		}
+
+		handlerErr := pool.callHandler(msg)
+
+		if handlerErr != nil {
+			pool.reportException(jms20subset.CreateJMSException("MessageHandler returned an error",
+				"MessageHandlerFailed", handlerErr))
+		}
+
+		if pool.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+
+			// Each message is received under its own transaction, which is backed out
+			// if the handler was unable to process it.
+			if handlerErr == nil {
+				retErr = worker.context.Commit()
+			} else {
+				retErr = worker.context.Rollback()
+			}
+
+			if retErr != nil {
+				pool.reportException(retErr)
+			}
+		}
+	}
+}
+
+// callHandler passes the message to the handler, converting a panic into an error
+// so that it doesn't stop the goroutine (and the application).
+func (pool *ConsumerPool) callHandler(msg jms20subset.Message) (handlerErr error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			handlerErr = fmt.Errorf("MessageHandler panicked: %v", r)
+		}
+	}()
+
+	return pool.handler(msg)
+}
+
+// backoutMessage moves a message that has reached the backout threshold to the
+// backout requeue queue, in the same transaction as it was received. The message
+// keeps its message ID and context.
+//
+// If the message can't be moved then it is backed out onto the queue again.
+func (pool *ConsumerPool) backoutMessage(worker *consumerPoolWorker, msg jms20subset.Message) jms20subset.JMSException {
+
+	if worker.backoutQueue == nil {
+		worker.context.Rollback()
+		return jms20subset.CreateJMSException(
+			"Message "+msg.GetJMSMessageID()+" has reached the backout threshold but the queue has no BOQNAME",
+			"BackoutQueueNotDefined", nil)
+	}
+
+	producer := worker.context.CreateProducer().(*ProducerImpl)
+
+	retErr := producer.forwardMessage(worker.backoutQueue, msg, worker.consumer)
+	if retErr != nil {
+		worker.context.Rollback()
+		return retErr
+	}
+
+	return worker.context.Commit()
+}
+
+// getBackoutCount returns the number of times that a received message has
+// previously been backed out.
+func getBackoutCount(msg jms20subset.Message) int {
+
+	var mqmd *ibmmq.MQMD
+
+	switch typedMsg := msg.(type) {
+	case *TextMessageImpl:
+		mqmd = typedMsg.mqmd
+	case *BytesMessageImpl:
+		mqmd = typedMsg.mqmd
+	case *ReportMessageImpl:
+		mqmd = typedMsg.mqmd
+	}
+
+	if mqmd == nil {
+		return 0
+	}
+
+	return int(mqmd.BackoutCount)
+}
+
+// createInvalidConcurrencyException returns the error for a negative number of consumers.
+func createInvalidConcurrencyException(concurrency int) jms20subset.JMSException {
+	return jms20subset.CreateJMSException("Invalid concurrency specified: "+strconv.Itoa(concurrency),
+		"InvalidConcurrency", nil)
+}
+
+// autoScale periodically checks the depth of the queue and adjusts the number of
+// consumers to match, until the stop channel is closed.
+func (pool *ConsumerPool) autoScale(stop chan struct{}) {
+
+	// Use a separate connection to check the queue depth so that it doesn't
+	// interfere with the consumers.
+	context, retErr := pool.cf.CreateContext()
+	if retErr != nil {
+		pool.reportException(retErr)
+		if context != nil {
+			context.Close()
+		}
+		return
+	}
+	defer context.Close()
+
+	ticker := time.NewTicker(pool.scaleInterval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		attrs, retErr := context.InquireQueue(pool.dest)
+		if retErr != nil {
+			pool.reportException(retErr)
+			continue
+		}
+
+		depth := attrs.CurrentDepth
+
+		// Run one consumer for every messagesPerConsumer messages, within the limits.
+		desired := (depth + pool.messagesPerConsumer - 1) / pool.messagesPerConsumer
+		if desired < pool.minConcurrency {
+			desired = pool.minConcurrency
+		}
+		if desired > pool.maxConcurrency {
+			desired = pool.maxConcurrency
+		}
+
+		pool.poolLock.Lock()
+		if pool.running && desired != len(pool.workers) {
+			retErr = pool.scaleToInternal(desired)
+		}
+		pool.poolLock.Unlock()
+
+		if retErr != nil {
+			pool.reportException(retErr)
+		}
+	}
+}
+
+// reportException passes an error to the exception listener if one has been set,
+// or otherwise prints it to the console.
+func (pool *ConsumerPool) reportException(retErr jms20subset.JMSException) {
+
+	if pool.exceptionListener != nil {
+		pool.exceptionListener(retErr)
+	} else {
+		fmt.Println("ConsumerPool", retErr)
+	}
+}
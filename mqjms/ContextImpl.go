@@ -11,7 +11,9 @@ package mqjms
 
 import (
 	"fmt"
+	"runtime/debug"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
@@ -21,25 +23,152 @@ import (
 // ContextImpl encapsulates the objects necessary to maintain an active
 // connection to an IBM MQ queue manager.
 type ContextImpl struct {
-	qMgr              ibmmq.MQQueueManager
-	ctxLock           *sync.Mutex // Mutex to synchronize MQRC calls to the queue manager
-	sessionMode       int
-	receiveBufferSize int
-	sendCheckCount    int
-	sendCheckCountInc *int // Internal counter to keep track of async-put messages sent
+	qMgr                ibmmq.MQQueueManager
+	ctxLock             *sync.Mutex // Mutex to synchronize MQRC calls to the queue manager
+	sessionMode         int
+	receiveBufferSize   int
+	maxDecompressedSize int
+	sendCheckCount      int
+	sendCheckCountInc   *int // Internal counter to keep track of async-put messages sent
+	protectionPolicies  map[string]ProtectionPolicy
+	poolLease           uint64 // Identifies the Borrow from a ContextPool that returned this context
 }
 
 // CreateQueue implements the logic necessary to create a provider-specific
 // object representing an IBM MQ queue.
 func (ctx ContextImpl) CreateQueue(queueName string) jms20subset.Queue {
 
+	// Queues described by a URI (for example a reply destination from an application
+	// using the IBM MQ classes for JMS) carry their own attributes.
+	if strings.HasPrefix(queueName, queueURIPrefix) {
+
+		queue, err := parseQueueURI(queueName)
+		if err != nil {
+			// Normally we would throw an error here to indicate that an invalid value
+			// was specified, however the JMS signature of this method does not allow us
+			// to return an error object. Instead we settle for printing an error message
+			// to the console, and the invalid parts of the URI are left at their defaults.
+			fmt.Println("Invalid queue URI specified: " + queueName + " (" + err.Error() + ")")
+		}
+
+		return queue
+	}
+
 	// Store the name of the queue
-	queue := QueueImpl{
-		queueName:       queueName,
-		putAsyncAllowed: jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+	return newQueueImpl(queueName)
+}
+
+// InquireQueue queries the queue manager for the current depth and configuration
+// attributes of the specified queue.
+func (ctx ContextImpl) InquireQueue(dest jms20subset.Destination) (jms20subset.QueueAttributes, jms20subset.JMSException) {
+
+	var attrs jms20subset.QueueAttributes
+
+	selectors := []int32{
+		ibmmq.MQIA_CURRENT_Q_DEPTH,
+		ibmmq.MQIA_MAX_Q_DEPTH,
+		ibmmq.MQIA_OPEN_INPUT_COUNT,
+		ibmmq.MQIA_OPEN_OUTPUT_COUNT,
+		ibmmq.MQIA_BACKOUT_THRESHOLD,
+		ibmmq.MQCA_BACKOUT_REQ_Q_NAME,
+		ibmmq.MQIA_MAX_MSG_LENGTH,
+		ibmmq.MQIA_PROPERTY_CONTROL,
+		ibmmq.MQIA_INHIBIT_GET,
+		ibmmq.MQIA_INHIBIT_PUT,
 	}
 
-	return queue
+	values, retErr := ctx.inquireObject(ibmmq.MQOT_Q, dest.GetDestinationName(), selectors)
+
+	if retErr == nil {
+
+		// Integer attributes are returned as int32 and character attributes as
+		// (blank padded) strings.
+		attrs.CurrentDepth = int(values[ibmmq.MQIA_CURRENT_Q_DEPTH].(int32))
+		attrs.MaxDepth = int(values[ibmmq.MQIA_MAX_Q_DEPTH].(int32))
+		attrs.OpenInputCount = int(values[ibmmq.MQIA_OPEN_INPUT_COUNT].(int32))
+		attrs.OpenOutputCount = int(values[ibmmq.MQIA_OPEN_OUTPUT_COUNT].(int32))
+		attrs.BackoutThreshold = int(values[ibmmq.MQIA_BACKOUT_THRESHOLD].(int32))
+		attrs.BackoutRequeueQName = strings.TrimSpace(values[ibmmq.MQCA_BACKOUT_REQ_Q_NAME].(string))
+		attrs.MaxMsgLength = int(values[ibmmq.MQIA_MAX_MSG_LENGTH].(int32))
+		attrs.PropertyControl = int(values[ibmmq.MQIA_PROPERTY_CONTROL].(int32))
+		attrs.GetInhibited = values[ibmmq.MQIA_INHIBIT_GET].(int32) == ibmmq.MQQA_GET_INHIBITED
+		attrs.PutInhibited = values[ibmmq.MQIA_INHIBIT_PUT].(int32) == ibmmq.MQQA_PUT_INHIBITED
+
+	}
+
+	return attrs, retErr
+}
+
+// ContextImpl_PROVIDER_NAME is the name of this JMS provider.
+const ContextImpl_PROVIDER_NAME string = "IBM MQ JMS 2.0 for Golang"
+
+// ContextImpl_MODULE_PATH is the path of the Go module that contains this provider.
+const ContextImpl_MODULE_PATH string = "github.com/ibm-messaging/mq-golang-jms20"
+
+// GetMetaData returns information about this provider and the queue manager to
+// which the context is connected, which is queried from the queue manager object.
+func (ctx ContextImpl) GetMetaData() (jms20subset.ConnectionMetaData, jms20subset.JMSException) {
+
+	metaData := jms20subset.ConnectionMetaData{
+		JMSVersion:          "2.0",
+		JMSMajorVersion:     2,
+		JMSMinorVersion:     0,
+		JMSProviderName:     ContextImpl_PROVIDER_NAME,
+		ProviderVersion:     getProviderVersion(),
+		ClientHeaderVersion: formatCommandLevel(int(ibmmq.MQCMDL_CURRENT_LEVEL)),
+	}
+
+	selectors := []int32{
+		ibmmq.MQCA_Q_MGR_NAME,
+		ibmmq.MQCA_Q_MGR_IDENTIFIER,
+		ibmmq.MQIA_COMMAND_LEVEL,
+		ibmmq.MQIA_PLATFORM,
+		ibmmq.MQIA_CODED_CHAR_SET_ID,
+	}
+
+	// An empty object name refers to the queue manager that we are connected to.
+	values, retErr := ctx.inquireObject(ibmmq.MQOT_Q_MGR, "", selectors)
+
+	if retErr == nil {
+
+		metaData.QueueManagerName = strings.TrimSpace(values[ibmmq.MQCA_Q_MGR_NAME].(string))
+		metaData.QueueManagerIdentifier = strings.TrimSpace(values[ibmmq.MQCA_Q_MGR_IDENTIFIER].(string))
+		metaData.CommandLevel = int(values[ibmmq.MQIA_COMMAND_LEVEL].(int32))
+		metaData.Platform = int(values[ibmmq.MQIA_PLATFORM].(int32))
+		metaData.PlatformName = ibmmq.MQItoString("PL", metaData.Platform)
+		metaData.CodedCharSetId = int(values[ibmmq.MQIA_CODED_CHAR_SET_ID].(int32))
+
+	}
+
+	return metaData, retErr
+}
+
+// getProviderVersion returns the version of this module, as recorded in the
+// build information of the application.
+func getProviderVersion() string {
+
+	version := "(devel)"
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+
+		if buildInfo.Main.Path == ContextImpl_MODULE_PATH {
+			version = buildInfo.Main.Version
+		}
+
+		for _, dep := range buildInfo.Deps {
+			if dep.Path == ContextImpl_MODULE_PATH {
+				version = dep.Version
+			}
+		}
+	}
+
+	return version
+}
+
+// formatCommandLevel converts an MQ command level such as 940 into the
+// corresponding version, such as "9.4.0".
+func formatCommandLevel(level int) string {
+	return strconv.Itoa(level/100) + "." + strconv.Itoa(level/10%10) + "." + strconv.Itoa(level%10)
 }
 
 // CreateProducer implements the logic necessary to create a JMSProducer object
@@ -66,6 +195,20 @@ func (ctx ContextImpl) CreateConsumer(dest jms20subset.Destination) (jms20subset
 // CreateConsumerWithSelector creates a consumer object that allows an application to
 // receive messages that match the specified selector from the given Destination.
 func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination, selector string) (jms20subset.JMSConsumer, jms20subset.JMSException) {
+	return ctx.createConsumerInternal(dest, selector, false)
+}
+
+// CreateGroupConsumer creates a consumer object that allows an application to
+// receive message groups from the given Destination. Messages are only received
+// once every message in their group has arrived on the queue, and the messages
+// in each group are received in the order that they were sent.
+func (ctx ContextImpl) CreateGroupConsumer(dest jms20subset.Destination) (jms20subset.JMSConsumer, jms20subset.JMSException) {
+	return ctx.createConsumerInternal(dest, "", true)
+}
+
+// createConsumerInternal provides the common logic for opening a queue in order
+// to create a consumer.
+func (ctx ContextImpl) createConsumerInternal(dest jms20subset.Destination, selector string, groupOrder bool) (jms20subset.JMSConsumer, jms20subset.JMSException) {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
@@ -89,6 +232,7 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 	var openOptions int32
 	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
 	openOptions |= ibmmq.MQOO_INPUT_AS_Q_DEF
+	openOptions |= ibmmq.MQOO_SAVE_ALL_CONTEXT // So that received messages can be forwarded with their context
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = dest.GetDestinationName()
 
@@ -103,9 +247,11 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 		// Success - store the necessary objects away for later use to receive
 		// messages.
 		consumer = ConsumerImpl{
-			ctx:      ctx,
-			qObject:  qObject,
-			selector: selector,
+			ctx:        ctx,
+			qObject:    qObject,
+			dest:       dest,
+			selector:   selector,
+			groupOrder: groupOrder,
 		}
 
 	} else {
@@ -124,18 +270,59 @@ func (ctx ContextImpl) CreateConsumerWithSelector(dest jms20subset.Destination,
 // CreateBrowser creates a consumer for the specified Destination so that
 // an application can look at messages without removing them.
 func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.QueueBrowser, jms20subset.JMSException) {
+	return ctx.CreateBrowserWithSelector(dest, "")
+}
+
+// CreateBrowserWithSelector creates a consumer for the specified Destination so
+// that an application can look at the messages that match the specified
+// selector without removing them.
+func (ctx ContextImpl) CreateBrowserWithSelector(dest jms20subset.Destination, selector string) (jms20subset.QueueBrowser, jms20subset.JMSException) {
+	return ctx.createBrowserInternal(dest, selector, false)
+}
+
+// CreateCooperativeBrowser creates a browser for the specified Destination that
+// cooperates with the other cooperative browsers of the queue, so that each
+// message is only returned to one of them. This allows several dispatchers to
+// share out the messages on a queue to workers, who can then receive each
+// message using a JMSMessageID selector.
+//
+// Each message is marked as it is browsed, and is skipped by the other
+// cooperative browsers until it is received, or until the mark expires after
+// the MsgMarkBrowseInterval (MARKINT) of the queue manager.
+func (ctx ContextImpl) CreateCooperativeBrowser(dest jms20subset.Destination) (jms20subset.QueueBrowser, jms20subset.JMSException) {
+	return ctx.createBrowserInternal(dest, "", true)
+}
+
+// createBrowserInternal provides the common logic for opening a queue in order
+// to create a browser.
+func (ctx ContextImpl) createBrowserInternal(dest jms20subset.Destination, selector string, cooperative bool) (jms20subset.QueueBrowser, jms20subset.JMSException) {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
 	ctx.ctxLock.Lock()
 	defer ctx.ctxLock.Unlock()
 
+	// First validate the selector string format, in the same way as for a
+	// consumer (it is applied to each browse step by GetNext)
+	if selector != "" {
+		getmqmd := ibmmq.NewMQMD()
+		gmo := ibmmq.NewMQGMO()
+
+		selectorErr := applySelector(selector, getmqmd, gmo)
+		if selectorErr != nil {
+			return nil, jms20subset.CreateJMSException("Invalid selector syntax", "MQJMS0004", selectorErr)
+		}
+	}
+
 	// Set up the necessary objects to open the queue
 	mqod := ibmmq.NewMQOD()
 	var openOptions int32
 	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
 	openOptions |= ibmmq.MQOO_INPUT_AS_Q_DEF
 	openOptions |= ibmmq.MQOO_BROWSE // This is the important part for browsing!
+	if cooperative {
+		openOptions |= ibmmq.MQOO_CO_OP
+	}
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = dest.GetDestinationName()
 
@@ -150,14 +337,17 @@ func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.
 		// Success - store the necessary objects away for later use to receive
 		// messages.
 		consumer := ConsumerImpl{
-			ctx:     ctx,
-			qObject: qObject,
+			ctx:      ctx,
+			qObject:  qObject,
+			dest:     dest,
+			selector: selector,
 		}
 
 		brse := int32(ibmmq.MQGMO_BROWSE_FIRST)
 
 		browser = &BrowserImpl{
 			browseOption: &brse,
+			cooperative:  cooperative,
 			ConsumerImpl: consumer,
 		}
 
@@ -178,20 +368,22 @@ func (ctx ContextImpl) CreateBrowser(dest jms20subset.Destination) (jms20subset.
 func (ctx ContextImpl) CreateTextMessage() jms20subset.TextMessage {
 
 	var bodyStr *string
-	thisMsgHandle := ctx.createMsgHandle(ctx.qMgr)
+	handleTracker := ctx.createMsgHandle(ctx.qMgr)
 
 	return &TextMessageImpl{
 		bodyStr: bodyStr,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:     handleTracker.msgHandle,
+			handleTracker: handleTracker,
+			ctxLock:       ctx.ctxLock,
 		},
 	}
 }
 
 // createMsgHandle creates a new message handle object that can be used to
-// store and retrieve message properties.
-func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) ibmmq.MQMessageHandle {
+// store and retrieve message properties, along with the tracker that deletes it
+// once it is no longer required.
+func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) *messageHandleTracker {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
@@ -201,10 +393,9 @@ func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) ibmmq.MQMessag
 	cmho := ibmmq.NewMQCMHO()
 	thisMsgHandle, err := qMgr.CrtMH(cmho)
 
-	// Set a finalizer on the message handle to allow it to be deleted
-	// when it is no longer referenced by an active object, to reduce/prevent
-	// memory leaks.
-	setMessageHandlerFinalizer(thisMsgHandle, ctx.ctxLock)
+	// Track the message handle so that it is deleted when the message is closed,
+	// or when it is no longer referenced by an active object.
+	handleTracker := newMessageHandleTracker(&thisMsgHandle, ctx.ctxLock)
 
 	if err != nil {
 		// No easy way to pass this error back to the application without
@@ -213,7 +404,7 @@ func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) ibmmq.MQMessag
 		fmt.Println(err)
 	}
 
-	return thisMsgHandle
+	return handleTracker
 
 }
 
@@ -221,13 +412,14 @@ func (ctx ContextImpl) createMsgHandle(qMgr ibmmq.MQQueueManager) ibmmq.MQMessag
 // and initialise it with the chosen text string.
 func (ctx ContextImpl) CreateTextMessageWithString(txt string) jms20subset.TextMessage {
 
-	thisMsgHandle := ctx.createMsgHandle(ctx.qMgr)
+	handleTracker := ctx.createMsgHandle(ctx.qMgr)
 
 	msg := &TextMessageImpl{
 		bodyStr: &txt,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:     handleTracker.msgHandle,
+			handleTracker: handleTracker,
+			ctxLock:       ctx.ctxLock,
 		},
 	}
 
@@ -238,13 +430,14 @@ func (ctx ContextImpl) CreateTextMessageWithString(txt string) jms20subset.TextM
 func (ctx ContextImpl) CreateBytesMessage() jms20subset.BytesMessage {
 
 	var thisBodyBytes *[]byte
-	thisMsgHandle := ctx.createMsgHandle(ctx.qMgr)
+	handleTracker := ctx.createMsgHandle(ctx.qMgr)
 
 	return &BytesMessageImpl{
 		bodyBytes: thisBodyBytes,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:     handleTracker.msgHandle,
+			handleTracker: handleTracker,
+			ctxLock:       ctx.ctxLock,
 		},
 	}
 }
@@ -252,17 +445,46 @@ func (ctx ContextImpl) CreateBytesMessage() jms20subset.BytesMessage {
 // CreateBytesMessageWithBytes is a JMS standard mechanism for creating a BytesMessage.
 func (ctx ContextImpl) CreateBytesMessageWithBytes(bytes []byte) jms20subset.BytesMessage {
 
-	thisMsgHandle := ctx.createMsgHandle(ctx.qMgr)
+	handleTracker := ctx.createMsgHandle(ctx.qMgr)
 
 	return &BytesMessageImpl{
 		bodyBytes: &bytes,
 		MessageImpl: MessageImpl{
-			msgHandle: &thisMsgHandle,
-			ctxLock:   ctx.ctxLock,
+			msgHandle:     handleTracker.msgHandle,
+			handleTracker: handleTracker,
+			ctxLock:       ctx.ctxLock,
 		},
 	}
 }
 
+// CreateMessageFromValue creates a BytesMessage containing the value encoded by
+// the specified Codec, with the MQ format and codec name set on the message.
+func (ctx ContextImpl) CreateMessageFromValue(v interface{}, codec jms20subset.Codec) (jms20subset.BytesMessage, jms20subset.JMSException) {
+
+	body, err := codec.Marshal(v)
+	if err != nil {
+		return nil, jms20subset.CreateMessageFormatException("BodyEncodeFailed", "BodyEncodeFailed", err)
+	}
+
+	msg := ctx.CreateBytesMessageWithBytes(body)
+
+	// Binary codecs return an empty format, which leaves the message without one.
+	if format := strings.TrimSpace(codec.GetFormat()); format != "" {
+		retErr := msg.SetStringProperty("JMS_IBM_Format", &format)
+		if retErr != nil {
+			return nil, retErr
+		}
+	}
+
+	codecName := codec.GetName()
+	retErr := msg.SetStringProperty(MessageImpl_PROPERTY_CODEC, &codecName)
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return msg, nil
+}
+
 // Commit confirms all messages that were sent under this transaction.
 func (ctx ContextImpl) Commit() jms20subset.JMSException {
 
@@ -370,6 +592,57 @@ func (ctx ContextImpl) Close() {
 
 }
 
+// ping checks that the connection to the queue manager is still usable by
+// making a lightweight inquiry against the queue manager object.
+func (ctx ContextImpl) ping() jms20subset.JMSException {
+
+	_, retErr := ctx.inquireObject(ibmmq.MQOT_Q_MGR, "", []int32{ibmmq.MQCA_Q_MGR_NAME})
+	return retErr
+
+}
+
+// inquireObject opens the specified MQ object for inquiry and returns the
+// values of the requested attribute selectors, keyed by selector.
+func (ctx ContextImpl) inquireObject(objectType int32, objectName string, selectors []int32) (map[int32]interface{}, jms20subset.JMSException) {
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	ctx.ctxLock.Lock()
+	defer ctx.ctxLock.Unlock()
+
+	// Set up the necessary objects to open the object for inquiry
+	mqod := ibmmq.NewMQOD()
+	var openOptions int32
+	openOptions = ibmmq.MQOO_FAIL_IF_QUIESCING
+	openOptions |= ibmmq.MQOO_INQUIRE
+	mqod.ObjectType = objectType
+	mqod.ObjectName = objectName
+
+	var values map[int32]interface{}
+	var retErr jms20subset.JMSException
+
+	// Invoke the MQ command to open the object, and if that is successful
+	// then ask for the attribute values before closing it again.
+	object, err := ctx.qMgr.Open(mqod, openOptions)
+
+	if err == nil {
+		values, err = object.Inq(selectors)
+		object.Close(0)
+	}
+
+	if err != nil {
+
+		// Error occurred - extract the failure details and return to the caller.
+		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		retErr = jms20subset.CreateJMSException(reason, errCode, err)
+
+	}
+
+	return values, retErr
+}
+
 // ContextImpl_TRANSACTED_ASYNCPUT_ACTIVE is an internal constant that indicates that
 // a transacted asynchronous put has taken place.
 const ContextImpl_TRANSACTED_ASYNCPUT_ACTIVE int = -100
@@ -0,0 +1,362 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+)
+
+// ContextPool maintains a set of connections to the queue manager that can be
+// shared between goroutines.
+//
+// Every call made using a JMSContext is serialized on that context, so goroutines
+// that need to send or receive messages in parallel should each Borrow their own
+// context from the pool and Return it when they have finished with it, rather
+// than sharing a single context.
+type ContextPool struct {
+	cf          ConnectionFactoryImpl
+	sessionMode int
+	minSize     int
+	maxSize     int
+	idleTimeout time.Duration
+
+	poolLock  *sync.Mutex            // Mutex to synchronize access to the idle and borrowed contexts
+	permits   chan struct{}          // One permit is held for each context that is in use
+	done      chan struct{}          // Closed when the pool is closed, to wake up waiting borrowers
+	idle      []pooledContext        // Contexts that are connected but not currently borrowed
+	borrowed  map[*sync.Mutex]uint64 // Lease of each context that is currently borrowed, keyed by its ctxLock
+	lastLease uint64                 // Lease that was given to the most recent borrower
+	closed    bool
+}
+
+// pooledContext records when an idle context was last returned to the pool so
+// that it can be expired or health checked when it is next borrowed.
+type pooledContext struct {
+	ctx      ContextImpl
+	lastUsed time.Time
+}
+
+// ContextPool_HEALTH_CHECK_INTERVAL is the length of time a context can sit
+// idle in the pool before it is checked with a call to the queue manager when
+// it is next borrowed.
+const ContextPool_HEALTH_CHECK_INTERVAL = 10 * time.Second
+
+// CreateContextPool creates a pool of contexts using the connection details in
+// the specified ConnectionFactory and session mode.
+//
+// minSize contexts are created immediately and are kept open for the lifetime of
+// the pool. Up to maxSize contexts can be borrowed at the same time. Contexts
+// over the minimum size that have been idle for longer than idleTimeout are closed;
+// an idleTimeout of zero or less means that idle contexts are never closed.
+func CreateContextPool(cf ConnectionFactoryImpl, sessionMode int, minSize int, maxSize int, idleTimeout time.Duration) (*ContextPool, jms20subset.JMSException) {
+
+	if minSize < 0 || maxSize < 1 || minSize > maxSize {
+		return nil, jms20subset.CreateJMSException("Invalid pool size: min="+strconv.Itoa(minSize)+
+			", max="+strconv.Itoa(maxSize), "ContextPoolInvalidSize", nil)
+	}
+
+	pool := &ContextPool{
+		cf:          cf,
+		sessionMode: sessionMode,
+		minSize:     minSize,
+		maxSize:     maxSize,
+		idleTimeout: idleTimeout,
+		poolLock:    &sync.Mutex{},
+		permits:     make(chan struct{}, maxSize),
+		done:        make(chan struct{}),
+		borrowed:    make(map[*sync.Mutex]uint64),
+	}
+
+	// Create the minimum number of contexts up front so that the first borrowers
+	// don't have to wait for a connection to be established.
+	for i := 0; i < minSize; i++ {
+
+		ctx, err := pool.createContext()
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		pool.idle = append(pool.idle, pooledContext{ctx: ctx, lastUsed: time.Now()})
+	}
+
+	return pool, nil
+}
+
+// Borrow returns a context from the pool for the exclusive use of the caller,
+// which must give it back by calling Return (or Discard) when it has finished.
+//
+// If maxSize contexts are already in use then the call waits for up to the
+// specified number of milliseconds for one to be returned. A value of zero or
+// less indicates to wait indefinitely. If the pool is closed while the caller is
+// waiting then an exception is returned.
+func (pool *ContextPool) Borrow(waitMillis int32) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	// Wait for a permit to use a context.
+	var timeout <-chan time.Time
+	if waitMillis > 0 {
+		timer := time.NewTimer(time.Duration(waitMillis) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case pool.permits <- struct{}{}:
+	case <-pool.done:
+		return nil, createPoolClosedException()
+	case <-timeout:
+		return nil, jms20subset.CreateJMSException("Timed out waiting for a context from the pool",
+			"ContextPoolExhausted", nil)
+	}
+
+	// Look for an idle context that is still usable.
+	for {
+
+		pooled, found, closed := pool.takeIdle()
+
+		if closed {
+			<-pool.permits
+			return nil, createPoolClosedException()
+		}
+
+		if !found {
+			break
+		}
+
+		// Only contexts that have been idle for a while are checked, so that a busy
+		// application is not slowed down by a call to the queue manager on every borrow.
+		if time.Since(pooled.lastUsed) < ContextPool_HEALTH_CHECK_INTERVAL || pooled.ctx.ping() == nil {
+			return pool.lend(pooled.ctx)
+		}
+
+		// The connection has failed, so close it and try the next one.
+		pooled.ctx.Close()
+	}
+
+	// No idle contexts, so create a new one.
+	ctx, err := pool.createContext()
+	if err != nil {
+		<-pool.permits
+		return nil, err
+	}
+
+	return pool.lend(ctx)
+}
+
+// lend records that the context is borrowed by the caller, who holds a permit,
+// unless the pool has been closed in the meantime.
+//
+// Each borrow is given a new lease, which is carried by the copy of the context
+// that is returned, so that a handle from an earlier borrow of the same connection
+// can't give back the context while it is in use by the current borrower.
+func (pool *ContextPool) lend(ctx ContextImpl) (jms20subset.JMSContext, jms20subset.JMSException) {
+
+	pool.poolLock.Lock()
+	closed := pool.closed
+	if !closed {
+		pool.lastLease++
+		ctx.poolLease = pool.lastLease
+		pool.borrowed[ctx.ctxLock] = ctx.poolLease
+	}
+	pool.poolLock.Unlock()
+
+	if closed {
+		ctx.Close()
+		<-pool.permits
+		return nil, createPoolClosedException()
+	}
+
+	return ctx, nil
+}
+
+// release removes the context from the set of borrowed contexts, returning
+// false if it was not borrowed from this pool, or if it is a handle from a borrow
+// that has already been given back, in which case the caller doesn't hold a permit
+// for it. The caller must hold the poolLock.
+func (pool *ContextPool) release(context jms20subset.JMSContext) (ContextImpl, bool) {
+
+	// Borrow only ever hands out a ContextImpl, so any other type of context
+	// can't have come from this pool.
+	ctx, ok := context.(ContextImpl)
+	if !ok || ctx.poolLease == 0 {
+		return ctx, false
+	}
+
+	lease, borrowed := pool.borrowed[ctx.ctxLock]
+	if !borrowed || lease != ctx.poolLease {
+		return ctx, false
+	}
+
+	delete(pool.borrowed, ctx.ctxLock)
+	return ctx, true
+}
+
+// Return gives a context that was obtained from Borrow back to the pool so that
+// it can be used by another goroutine.
+//
+// Any transaction that has not been committed on a transacted context is rolled back.
+// Contexts that were not borrowed from this pool, or that have already been given
+// back, are ignored.
+func (pool *ContextPool) Return(context jms20subset.JMSContext) {
+
+	pool.poolLock.Lock()
+	ctx, owned := pool.release(context)
+	pool.poolLock.Unlock()
+
+	if !owned {
+		return
+	}
+
+	if pool.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+		ctx.Rollback()
+	}
+
+	pool.poolLock.Lock()
+
+	if pool.closed {
+		pool.poolLock.Unlock()
+		ctx.Close()
+	} else {
+		pool.idle = append(pool.idle, pooledContext{ctx: ctx, lastUsed: time.Now()})
+		expired := pool.removeExpired()
+		pool.poolLock.Unlock()
+
+		for _, expiredCtx := range expired {
+			expiredCtx.Close()
+		}
+	}
+
+	<-pool.permits
+}
+
+// Discard closes a context that was obtained from Borrow instead of giving it back
+// to the pool, for example because the application has seen an error that indicates
+// that the connection to the queue manager is broken.
+//
+// Contexts that were not borrowed from this pool, or that have already been given
+// back, are ignored.
+func (pool *ContextPool) Discard(context jms20subset.JMSContext) {
+
+	pool.poolLock.Lock()
+	ctx, owned := pool.release(context)
+	pool.poolLock.Unlock()
+
+	if !owned {
+		return
+	}
+
+	ctx.Close()
+
+	<-pool.permits
+}
+
+// Close closes all of the idle contexts in the pool, and wakes up any goroutines
+// that are waiting to borrow a context with an exception. Contexts that are
+// currently borrowed are closed when they are returned.
+func (pool *ContextPool) Close() {
+
+	pool.poolLock.Lock()
+	idle := pool.idle
+	pool.idle = nil
+	if !pool.closed {
+		pool.closed = true
+		close(pool.done)
+	}
+	pool.poolLock.Unlock()
+
+	for _, pooled := range idle {
+		pooled.ctx.Close()
+	}
+}
+
+// takeIdle removes the most recently used idle context from the pool, after first
+// closing any contexts that have been idle for longer than the idle timeout.
+func (pool *ContextPool) takeIdle() (pooledContext, bool, bool) {
+
+	pool.poolLock.Lock()
+
+	if pool.closed {
+		pool.poolLock.Unlock()
+		return pooledContext{}, false, true
+	}
+
+	expired := pool.removeExpired()
+
+	var pooled pooledContext
+	found := false
+
+	// Take from the end so that the contexts that are used most often stay warm and
+	// the rest have the chance to expire.
+	if len(pool.idle) > 0 {
+		pooled = pool.idle[len(pool.idle)-1]
+		pool.idle = pool.idle[:len(pool.idle)-1]
+		found = true
+	}
+
+	pool.poolLock.Unlock()
+
+	for _, expiredCtx := range expired {
+		expiredCtx.Close()
+	}
+
+	return pooled, found, false
+}
+
+// removeExpired removes the contexts that have been idle for longer than the idle
+// timeout, without taking the pool below its minimum size. The caller must hold the
+// poolLock, and is responsible for closing the contexts that are returned.
+func (pool *ContextPool) removeExpired() []ContextImpl {
+
+	var expired []ContextImpl
+
+	if pool.idleTimeout <= 0 {
+		return expired
+	}
+
+	// The number of contexts that are in use is the number of permits that are held,
+	// less the caller who is in the middle of borrowing or returning.
+	total := len(pool.idle) + len(pool.permits) - 1
+
+	// Idle contexts are held in order of when they were returned, so the ones that
+	// have expired are at the start of the slice.
+	for len(pool.idle) > 0 && total > pool.minSize &&
+		time.Since(pool.idle[0].lastUsed) > pool.idleTimeout {
+
+		expired = append(expired, pool.idle[0].ctx)
+		pool.idle = pool.idle[1:]
+		total--
+	}
+
+	return expired
+}
+
+// createContext establishes a new connection to the queue manager.
+func (pool *ContextPool) createContext() (ContextImpl, jms20subset.JMSException) {
+
+	context, err := pool.cf.CreateContextWithSessionMode(pool.sessionMode)
+	if err != nil {
+		if context != nil {
+			context.Close()
+		}
+		return ContextImpl{}, err
+	}
+
+	return context.(ContextImpl), nil
+}
+
+// createPoolClosedException creates the exception that is returned when a context
+// is borrowed from a pool that has been closed.
+func createPoolClosedException() jms20subset.JMSException {
+	return jms20subset.CreateJMSException("The context pool has been closed", "ContextPoolClosed", nil)
+}
@@ -0,0 +1,499 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// DLQAction_RETRY puts the message back on the queue that it was originally
+// addressed to.
+const DLQAction_RETRY string = "RETRY"
+
+// DLQAction_FORWARD puts the message on the queue named by the rule.
+const DLQAction_FORWARD string = "FWD"
+
+// DLQAction_DISCARD removes the message from the dead-letter queue.
+const DLQAction_DISCARD string = "DISCARD"
+
+// DLQAction_IGNORE leaves the message on the dead-letter queue.
+const DLQAction_IGNORE string = "IGNORE"
+
+// DLQHandler_DEFAULT_WAIT_INTERVAL is the default interval between each pass
+// over the dead-letter queue when the handler is started.
+const DLQHandler_DEFAULT_WAIT_INTERVAL = 60 * time.Second
+
+// DLQRule is one entry in the rules table of a DLQHandler, in the style of the
+// runmqdlq dead-letter queue handler.
+//
+// The pattern fields are compared with the dead-letter header of each message,
+// where a zero value matches any message, and a name that ends with an asterisk
+// matches any name that starts with the same characters. The first rule that
+// matches a message determines the action that is taken.
+type DLQRule struct {
+	Reason       int32  // Reason code in the dead-letter header, for example ibmmq.MQRC_Q_FULL
+	DestQName    string // Name of the queue the message was originally addressed to
+	DestQMgrName string // Name of the queue manager the message was originally addressed to
+	PutApplName  string // Name of the application that put the message on the dead-letter queue
+	Format       string // Format of the original message, for example ibmmq.MQFMT_STRING
+
+	Action          string // One of the DLQAction_* values
+	ForwardQName    string // Queue that messages are put to by DLQAction_FORWARD
+	ForwardQMgrName string // Optional queue manager that messages are put to by DLQAction_FORWARD
+
+	// Retry is the number of times that the action is attempted for a message
+	// before the handler moves on to the next rule that matches it. Defaults to 1.
+	Retry int
+}
+
+// DLQHandler processes the messages on a dead-letter queue according to a table
+// of rules, retrying, forwarding or discarding each one.
+//
+// Each message is moved under a transaction, so that it is only removed from the
+// dead-letter queue if the action is successful. Messages that do not match any
+// rule, or whose actions fail, are left on the dead-letter queue.
+type DLQHandler struct {
+	cf    ConnectionFactoryImpl
+	dlq   jms20subset.Destination
+	rules []DLQRule
+
+	waitInterval      time.Duration
+	exceptionListener func(jms20subset.JMSException)
+
+	handlerLock *sync.Mutex // Mutex to serialize passes over the dead-letter queue
+	attempts    map[dlqAttemptKey]int
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// dlqAttemptKey identifies the number of attempts at applying one rule to a message.
+type dlqAttemptKey struct {
+	msgID string
+	rule  int
+}
+
+// NewDLQHandler creates a handler that applies the rules to the messages on the
+// specified dead-letter queue. The handler does not connect to the queue manager
+// until ProcessMessages or Start is called.
+func NewDLQHandler(cf ConnectionFactoryImpl, dlq jms20subset.Destination, rules []DLQRule) *DLQHandler {
+
+	return &DLQHandler{
+		cf:           cf,
+		dlq:          dlq,
+		rules:        rules,
+		waitInterval: DLQHandler_DEFAULT_WAIT_INTERVAL,
+		handlerLock:  &sync.Mutex{},
+		attempts:     make(map[dlqAttemptKey]int),
+	}
+}
+
+// SetWaitInterval sets the interval between each pass over the dead-letter queue
+// when the handler is started. Must be called before Start.
+func (handler *DLQHandler) SetWaitInterval(interval time.Duration) *DLQHandler {
+
+	if interval > 0 {
+		handler.waitInterval = interval
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid WaitInterval specified: " + interval.String())
+	}
+
+	return handler
+}
+
+// SetExceptionListener registers a function that is called when the handler is
+// unable to process the dead-letter queue, or the action for a message fails.
+// If no listener is set then the errors are printed to the console.
+func (handler *DLQHandler) SetExceptionListener(listener func(jms20subset.JMSException)) *DLQHandler {
+	handler.exceptionListener = listener
+	return handler
+}
+
+// ProcessMessages makes a single pass over the messages that are currently on
+// the dead-letter queue, and returns the number of messages that were removed
+// from it.
+func (handler *DLQHandler) ProcessMessages() (int, jms20subset.JMSException) {
+
+	browseContext, actionContext, retErr := handler.createContexts()
+	if retErr != nil {
+		return 0, retErr
+	}
+	defer browseContext.Close()
+	defer actionContext.Close()
+
+	return handler.processMessagesInternal(browseContext, actionContext)
+}
+
+// Start runs a pass over the dead-letter queue at every wait interval, until
+// Stop is called.
+func (handler *DLQHandler) Start() jms20subset.JMSException {
+
+	handler.handlerLock.Lock()
+	defer handler.handlerLock.Unlock()
+
+	if handler.stop != nil {
+		return nil
+	}
+
+	browseContext, actionContext, retErr := handler.createContexts()
+	if retErr != nil {
+		return retErr
+	}
+
+	handler.stop = make(chan struct{})
+	handler.done = make(chan struct{})
+	go handler.run(browseContext, actionContext, handler.stop, handler.done)
+
+	return nil
+}
+
+// Stop waits for the current pass over the dead-letter queue to finish, and
+// then stops the handler.
+func (handler *DLQHandler) Stop() {
+
+	handler.handlerLock.Lock()
+	stop := handler.stop
+	done := handler.done
+	handler.stop = nil
+	handler.done = nil
+	handler.handlerLock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// createContexts creates the connections used to browse the dead-letter queue,
+// and to move each message under a transaction. A browse cannot be made under
+// syncpoint, so the two need separate connections.
+func (handler *DLQHandler) createContexts() (jms20subset.JMSContext, jms20subset.JMSContext, jms20subset.JMSException) {
+
+	browseContext, retErr := handler.cf.CreateContext()
+	if retErr != nil {
+		if browseContext != nil {
+			browseContext.Close()
+		}
+		return nil, nil, retErr
+	}
+
+	actionContext, retErr := handler.cf.CreateContextWithSessionMode(jms20subset.JMSContextSESSIONTRANSACTED)
+	if retErr != nil {
+		if actionContext != nil {
+			actionContext.Close()
+		}
+		browseContext.Close()
+		return nil, nil, retErr
+	}
+
+	return browseContext, actionContext, nil
+}
+
+// run is the loop that is run by the goroutine of a started handler.
+func (handler *DLQHandler) run(browseContext jms20subset.JMSContext, actionContext jms20subset.JMSContext,
+	stop chan struct{}, done chan struct{}) {
+
+	defer close(done)
+	defer browseContext.Close()
+	defer actionContext.Close()
+
+	ticker := time.NewTicker(handler.waitInterval)
+	defer ticker.Stop()
+
+	for {
+
+		_, retErr := handler.processMessagesInternal(browseContext, actionContext)
+		if retErr != nil {
+			handler.reportException(retErr)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processMessagesInternal browses the dead-letter queue and applies the rules to
+// each message that it finds.
+func (handler *DLQHandler) processMessagesInternal(browseContext jms20subset.JMSContext,
+	actionContext jms20subset.JMSContext) (int, jms20subset.JMSException) {
+
+	handler.handlerLock.Lock()
+	defer handler.handlerLock.Unlock()
+
+	browser, retErr := browseContext.CreateBrowser(handler.dlq)
+	if retErr != nil {
+		return 0, retErr
+	}
+	defer browser.Close()
+
+	enumeration, retErr := browser.GetEnumeration()
+	if retErr != nil {
+		return 0, retErr
+	}
+
+	removed := 0
+	seen := make(map[string]bool)
+
+	for {
+
+		browsedMsg, retErr := enumeration.GetNext()
+		if retErr != nil {
+			return removed, retErr
+		}
+
+		if browsedMsg == nil {
+			// Reached the end of the queue, so forget the attempts for any message
+			// that is no longer on it.
+			for key := range handler.attempts {
+				if !seen[key.msgID] {
+					delete(handler.attempts, key)
+				}
+			}
+
+			return removed, nil
+		}
+
+		seen[browsedMsg.GetJMSMessageID()] = true
+
+		if handler.processMessage(actionContext, browsedMsg) {
+			removed++
+		}
+	}
+}
+
+// processMessage applies the first rule that matches the message, and returns
+// true if the message was removed from the dead-letter queue.
+func (handler *DLQHandler) processMessage(context jms20subset.JMSContext, browsedMsg jms20subset.Message) bool {
+
+	dlh := getDeadLetterHeader(browsedMsg)
+	msgID := browsedMsg.GetJMSMessageID()
+
+	for i, rule := range handler.rules {
+
+		if !rule.matches(dlh) {
+			continue
+		}
+
+		if rule.Action == DLQAction_IGNORE {
+			return false
+		}
+
+		retry := rule.Retry
+		if retry <= 0 {
+			retry = 1
+		}
+
+		key := dlqAttemptKey{msgID: msgID, rule: i}
+		if handler.attempts[key] >= retry {
+			// This rule has been tried as many times as allowed, so move on to the
+			// next rule that matches the message.
+			continue
+		}
+
+		removed, retErr := handler.applyAction(context, rule, msgID)
+		if retErr != nil {
+			handler.attempts[key]++
+			handler.reportException(retErr)
+			return false
+		}
+
+		for j := range handler.rules {
+			delete(handler.attempts, dlqAttemptKey{msgID: msgID, rule: j})
+		}
+
+		return removed
+	}
+
+	// No rule matches the message, so it is left on the dead-letter queue.
+	return false
+}
+
+// applyAction removes the message from the dead-letter queue and carries out
+// the action of the rule, under a single transaction. Returns false if the
+// message has already been removed by another application.
+//
+// Messages that are retried or forwarded keep their message ID and context, as
+// they do with the runmqdlq handler.
+func (handler *DLQHandler) applyAction(context jms20subset.JMSContext, rule DLQRule, msgID string) (bool, jms20subset.JMSException) {
+
+	consumer, retErr := context.CreateConsumerWithSelector(handler.dlq, "JMSMessageID = '"+msgID+"'")
+	if retErr != nil {
+		return false, retErr
+	}
+	defer consumer.Close()
+
+	msg, retErr := consumer.ReceiveNoWait()
+	if retErr != nil || msg == nil {
+		// Back out anything that was received before the failure.
+		context.Rollback()
+		return false, retErr
+	}
+
+	var dest jms20subset.Queue
+
+	switch rule.Action {
+	case DLQAction_RETRY:
+		dlh := getDeadLetterHeader(msg)
+		if dlh == nil || dlh.DestQName == "" {
+			context.Rollback()
+			return false, jms20subset.CreateJMSException("Message has no dead-letter header to retry",
+				"NoDeadLetterHeader", nil)
+		}
+		dest = context.CreateQueue(dlh.DestQName)
+		if dlh.DestQMgrName != "" {
+			dest = dest.SetQueueManagerName(dlh.DestQMgrName)
+		}
+
+	case DLQAction_FORWARD:
+		dest = context.CreateQueue(rule.ForwardQName)
+		if rule.ForwardQMgrName != "" {
+			dest = dest.SetQueueManagerName(rule.ForwardQMgrName)
+		}
+
+	case DLQAction_DISCARD:
+		// Nothing to do other than commit the receive.
+
+	default:
+		context.Rollback()
+		return false, jms20subset.CreateJMSException("Invalid DLQ action "+rule.Action,
+			"InvalidDLQAction", nil)
+	}
+
+	if dest != nil {
+
+		producer := context.CreateProducer().(*ProducerImpl)
+
+		retErr = producer.forwardMessage(dest, msg, consumer)
+		if retErr != nil {
+			context.Rollback()
+			return false, retErr
+		}
+	}
+
+	retErr = context.Commit()
+	if retErr != nil {
+		return false, retErr
+	}
+
+	return true, nil
+}
+
+// matches returns true if the dead-letter header matches every pattern in the rule.
+func (rule DLQRule) matches(dlh *ibmmq.MQDLH) bool {
+
+	if dlh == nil {
+		// Only rules that don't depend on the header match a message without one.
+		return rule.Reason == ibmmq.MQRC_NONE && rule.DestQName == "" && rule.DestQMgrName == "" &&
+			rule.PutApplName == "" && rule.Format == ""
+	}
+
+	if rule.Reason != ibmmq.MQRC_NONE && rule.Reason != dlh.Reason {
+		return false
+	}
+
+	return matchesDLQPattern(rule.DestQName, dlh.DestQName) &&
+		matchesDLQPattern(rule.DestQMgrName, dlh.DestQMgrName) &&
+		matchesDLQPattern(rule.PutApplName, dlh.PutApplName) &&
+		matchesDLQPattern(rule.Format, dlh.Format)
+}
+
+// matchesDLQPattern compares a value from the dead-letter header with a pattern
+// from a rule, where an empty pattern matches any value and a trailing asterisk
+// matches any remaining characters.
+func matchesDLQPattern(pattern string, value string) bool {
+
+	pattern = strings.TrimSpace(pattern)
+	value = strings.TrimSpace(value)
+
+	if pattern == "" {
+		return true
+	}
+
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, pattern[:len(pattern)-1])
+	}
+
+	return pattern == value
+}
+
+// reportException passes an error to the exception listener if one has been set,
+// or otherwise prints it to the console.
+func (handler *DLQHandler) reportException(retErr jms20subset.JMSException) {
+
+	if handler.exceptionListener != nil {
+		handler.exceptionListener(retErr)
+	} else {
+		fmt.Println("DLQHandler", retErr)
+	}
+}
+
+// getDeadLetterHeader returns the dead-letter header of a message that was
+// received from a dead-letter queue, or nil if it doesn't have one.
+func getDeadLetterHeader(msg jms20subset.Message) *ibmmq.MQDLH {
+
+	switch typedMsg := msg.(type) {
+	case *TextMessageImpl:
+		return typedMsg.dlh
+	case *BytesMessageImpl:
+		return typedMsg.dlh
+	case *ReportMessageImpl:
+		return typedMsg.dlh
+	}
+
+	return nil
+}
+
+// stripDeadLetterHeader parses the MQDLH at the start of a message that was put
+// to a dead-letter queue, and returns the header along with the original body of
+// the message. The format, encoding and CCSID of the original message are
+// restored into the MQMD.
+//
+// Messages that don't start with a dead-letter header are returned unchanged.
+func stripDeadLetterHeader(getmqmd *ibmmq.MQMD, body []byte) (*ibmmq.MQDLH, []byte) {
+
+	if strings.TrimSpace(getmqmd.Format) != ibmmq.MQFMT_DEAD_LETTER_HEADER ||
+		len(body) < int(ibmmq.MQDLH_CURRENT_LENGTH) {
+		return nil, body
+	}
+
+	header, headerLen, err := ibmmq.GetHeader(getmqmd, body)
+	if err != nil {
+		return nil, body
+	}
+
+	dlh, ok := header.(*ibmmq.MQDLH)
+	if !ok || headerLen > len(body) {
+		return nil, body
+	}
+
+	getmqmd.Format = dlh.Format
+	getmqmd.Encoding = dlh.Encoding
+
+	// A CCSID of MQCCSI_INHERIT means that the original message uses the same
+	// CCSID as the header.
+	if dlh.CodedCharSetId != ibmmq.MQCCSI_INHERIT {
+		getmqmd.CodedCharSetId = dlh.CodedCharSetId
+	}
+
+	return dlh, body[headerLen:]
+}
@@ -0,0 +1,63 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"encoding/hex"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// GroupProducerImpl sends messages to a queue as message groups, by putting
+// them in logical order so that the queue manager assigns the group ID and
+// sequence number of each message.
+type GroupProducerImpl struct {
+	producer ProducerImpl
+	dest     jms20subset.Destination
+	qObject  ibmmq.MQObject
+	groupID  []byte
+}
+
+// Send adds a message to the current group, starting a new group if there
+// isn't one in progress.
+func (group *GroupProducerImpl) Send(msg jms20subset.Message) jms20subset.JMSException {
+	return group.producer.sendInternal(group.dest, msg, group, false)
+}
+
+// SendLast adds the final message to the current group, which completes the group.
+func (group *GroupProducerImpl) SendLast(msg jms20subset.Message) jms20subset.JMSException {
+	return group.producer.sendInternal(group.dest, msg, group, true)
+}
+
+// GetGroupID returns the JMSXGroupID of the current or most recent group.
+func (group *GroupProducerImpl) GetGroupID() string {
+
+	if group.groupID == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(group.groupID)
+}
+
+// Close closes the queue that was opened to send the messages.
+func (group *GroupProducerImpl) Close() {
+
+	if (ibmmq.MQObject{}) != group.qObject {
+
+		// Lock the context while we are making calls to the queue manager so that it
+		// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+		group.producer.ctx.ctxLock.Lock()
+		defer group.producer.ctx.ctxLock.Unlock()
+
+		group.qObject.Close(0)
+	}
+
+}
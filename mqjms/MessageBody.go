@@ -0,0 +1,270 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"encoding/json"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// BodyDecoder converts the body of a message into the value pointed to by v.
+type BodyDecoder func(body []byte, v interface{}) error
+
+// JSONDecoder is the BodyDecoder that is used by GetBody and ReceiveBody for
+// messages that don't record the name of a Codec, which decodes message bodies
+// that contain JSON.
+var JSONDecoder BodyDecoder = json.Unmarshal
+
+// GetBody returns the body of the message as the type T, in the style of the
+// Java JMS Message.getBody(Class<T>) method.
+//
+// A string is returned directly from a TextMessage and a []byte directly from a
+// BytesMessage, and any other type is decoded from the body of either kind of
+// message. If the message was created by CreateMessageFromValue then the body is
+// decoded by the registered Codec with the name recorded on the message, and
+// otherwise it is decoded as JSON. Returns nil if the message has no body.
+//
+// A MessageFormatException is returned if the body is the wrong kind for the
+// requested type, the codec is not registered, or the body cannot be decoded.
+func GetBody[T any](msg jms20subset.Message) (*T, jms20subset.JMSException) {
+
+	decoder := JSONDecoder
+
+	codecName, jmsErr := msg.GetStringProperty(MessageImpl_PROPERTY_CODEC)
+	if jmsErr != nil {
+		return nil, jmsErr
+	}
+
+	if codecName != nil {
+
+		codec, found := lookupCodec(*codecName)
+		if !found {
+			return nil, jms20subset.CreateMessageFormatException("Codec not registered: "+*codecName,
+				"UnknownCodec", nil)
+		}
+
+		decoder = codec.Unmarshal
+	}
+
+	return GetBodyWithDecoder[T](msg, decoder)
+}
+
+// GetBodyWithDecoder returns the body of the message as the type T, using the
+// specified BodyDecoder for types other than string and []byte.
+//
+// Note that since Golang does not allow multiple functions with the same
+// name and different parameters we must use a different function name.
+func GetBodyWithDecoder[T any](msg jms20subset.Message, decoder BodyDecoder) (*T, jms20subset.JMSException) {
+
+	var body []byte
+
+	switch typedMsg := msg.(type) {
+	case jms20subset.TextMessage:
+
+		text := typedMsg.GetText()
+		if text == nil {
+			return nil, nil
+		}
+
+		// Strings are returned without decoding.
+		if strPtr, ok := interface{}(text).(*T); ok {
+			return strPtr, nil
+		}
+
+		// A slice of bytes can only be read from a BytesMessage.
+		if _, ok := interface{}(&body).(*T); ok {
+			return nil, jms20subset.CreateMessageFormatException("MQJMS_DIR_MIN_NOTBYTES", "MQJMS6068", nil)
+		}
+
+		body = []byte(*text)
+
+	case jms20subset.BytesMessage:
+
+		bytes := typedMsg.ReadBytes()
+		if bytes == nil || len(*bytes) == 0 {
+			return nil, nil
+		}
+
+		// Slices of bytes are returned without decoding.
+		if bytesPtr, ok := interface{}(bytes).(*T); ok {
+			return bytesPtr, nil
+		}
+
+		// A string can only be read from a TextMessage.
+		var str string
+		if _, ok := interface{}(&str).(*T); ok {
+			return nil, jms20subset.CreateMessageFormatException("MQJMS_DIR_MIN_NOTTEXT", "MQJMS6068", nil)
+		}
+
+		body = *bytes
+
+	default:
+		return nil, jms20subset.CreateMessageFormatException("UnexpectedMessageType", "UnexpectedMessageType", nil)
+	}
+
+	value := new(T)
+	err := decoder(body, value)
+	if err != nil {
+		return nil, jms20subset.CreateMessageFormatException("BodyDecodeFailed", "BodyDecodeFailed", err)
+	}
+
+	return value, nil
+}
+
+// ReceiveBody receives the next message for the consumer and returns its body as
+// the type T, in the style of the Java JMS JMSConsumer.receiveBody(Class<T>) method.
+// See GetBody for details of how the body is converted.
+//
+// If a message is not immediately available the function will block for up to the
+// specified number of milliseconds to wait for one to become available, returning
+// nil if none arrives. A value of zero or less indicates to wait indefinitely.
+//
+// If the body cannot be converted to the type T then the message is not lost. When
+// the context is not transacted the message is received under syncpoint and backed
+// out onto the queue again, so that it can be received as a Message instead. Once
+// it has been backed out as many times as the backout threshold (BOTHRESH) of the
+// queue it is moved to the backout requeue queue (BOQNAME) instead, in the same way
+// as by a ConsumerPool, so that it doesn't prevent the messages behind it from
+// being received. When the context is transacted the message remains part of the
+// transaction, which the application can roll back.
+func ReceiveBody[T any](consumer jms20subset.JMSConsumer, waitMillis int32) (*T, jms20subset.JMSException) {
+	return receiveBodyInternal(consumer, true, waitMillis, GetBody[T])
+}
+
+// ReceiveBodyNoWait receives the next message for the consumer and returns its
+// body as the type T. If a message is not immediately available a nil is returned.
+// See ReceiveBody for what happens to a message whose body cannot be converted.
+func ReceiveBodyNoWait[T any](consumer jms20subset.JMSConsumer) (*T, jms20subset.JMSException) {
+	return receiveBodyInternal(consumer, false, 0, GetBody[T])
+}
+
+// ReceiveBodyWithDecoder receives the next message for the consumer and returns
+// its body as the type T, using the specified BodyDecoder for types other than
+// string and []byte. See ReceiveBody for what happens to a message whose body
+// cannot be converted.
+func ReceiveBodyWithDecoder[T any](consumer jms20subset.JMSConsumer, waitMillis int32, decoder BodyDecoder) (*T, jms20subset.JMSException) {
+
+	return receiveBodyInternal(consumer, true, waitMillis, func(msg jms20subset.Message) (*T, jms20subset.JMSException) {
+		return GetBodyWithDecoder[T](msg, decoder)
+	})
+}
+
+// receiveBodyInternal receives the next message for the consumer and converts its
+// body using getBody, backing out or requeuing the message if the body cannot be
+// converted and the context is not transacted.
+func receiveBodyInternal[T any](consumer jms20subset.JMSConsumer, wait bool, waitMillis int32,
+	getBody func(jms20subset.Message) (*T, jms20subset.JMSException)) (*T, jms20subset.JMSException) {
+
+	consumerImpl, ok := consumer.(ConsumerImpl)
+	if !ok {
+
+		var msg jms20subset.Message
+		var jmsErr jms20subset.JMSException
+
+		if wait {
+			msg, jmsErr = consumer.Receive(waitMillis)
+		} else {
+			msg, jmsErr = consumer.ReceiveNoWait()
+		}
+
+		if jmsErr != nil || msg == nil {
+			return nil, jmsErr
+		}
+
+		return getBody(msg)
+	}
+
+	gmo := ibmmq.NewMQGMO()
+	if wait {
+		if waitMillis <= 0 {
+			waitMillis = ibmmq.MQWI_UNLIMITED
+		}
+		gmo.Options |= ibmmq.MQGMO_WAIT
+		gmo.WaitInterval = waitMillis
+	}
+
+	// Receive the message under its own unit of work if the context isn't
+	// transacted, so that it can be backed out if the body can't be converted.
+	localTransaction := consumerImpl.ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED
+	if localTransaction {
+		gmo.Options |= ibmmq.MQGMO_SYNCPOINT
+	}
+
+	msg, jmsErr := consumerImpl.receiveInternal(gmo)
+
+	var value *T
+	if jmsErr == nil && msg != nil {
+		value, jmsErr = getBody(msg)
+	}
+
+	if localTransaction {
+		switch {
+		case jmsErr == nil:
+			if msg != nil {
+				jmsErr = consumerImpl.ctx.Commit()
+			}
+
+		case msg != nil:
+			// The message was received but its body can't be converted.
+			consumerImpl.requeueUnconvertibleMessage(msg)
+
+		case jmsErr.GetErrorCode() == "UnprotectFailed":
+			// The message failed verification and has been moved to the error queue.
+			consumerImpl.ctx.Commit()
+
+		default:
+			consumerImpl.ctx.Rollback()
+		}
+	}
+
+	// Only the body is returned to the application, so the message handle can be
+	// deleted straight away rather than waiting for the finalizer.
+	if msg != nil {
+		msg.Close()
+	}
+
+	if jmsErr != nil {
+		return nil, jmsErr
+	}
+
+	return value, nil
+}
+
+// requeueUnconvertibleMessage backs out a message whose body can't be converted,
+// which was received under syncpoint, so that it can be received again. If it has
+// already been backed out as many times as the backout threshold of the queue then
+// it is moved to the backout requeue queue instead, keeping its message ID and
+// context. If the queue has no backout requeue queue, or the queue can't be
+// inquired, then the message is backed out.
+func (consumer ConsumerImpl) requeueUnconvertibleMessage(msg jms20subset.Message) {
+
+	attrs, inqErr := consumer.ctx.InquireQueue(consumer.dest)
+	if inqErr != nil || attrs.BackoutThreshold <= 0 || attrs.BackoutRequeueQName == "" ||
+		getBackoutCount(msg) < attrs.BackoutThreshold {
+
+		consumer.ctx.Rollback()
+		return
+	}
+
+	producer := consumer.ctx.CreateProducer().(*ProducerImpl)
+
+	retErr := producer.forwardMessage(consumer.ctx.CreateQueue(attrs.BackoutRequeueQName), msg, consumer)
+	if retErr != nil {
+		consumer.ctx.Rollback()
+		return
+	}
+
+	retErr = consumer.ctx.Commit()
+	if retErr != nil {
+		consumer.ctx.Rollback()
+	}
+}
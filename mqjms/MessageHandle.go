@@ -0,0 +1,139 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// messageHandleTracker owns the MQ message handle of a message, so that it is
+// deleted exactly once; either when the application closes the message, or
+// otherwise by a finalizer when the message is garbage collected.
+type messageHandleTracker struct {
+	msgHandle *ibmmq.MQMessageHandle
+	ctxLock   *sync.Mutex
+	released  bool   // Guarded by ctxLock
+	stack     string // Where the message was created, if leak debugging is enabled
+}
+
+// leakDebugEnabled records whether SetMessageHandleLeakDebug has been enabled.
+var leakDebugEnabled atomic.Bool
+
+// leakReporter is called with the stack trace of each leaked message, guarded
+// by leakReporterLock as it is called from the finalizer goroutine.
+var leakReporter = printLeakedMessageHandle
+var leakReporterLock sync.Mutex
+
+// SetMessageHandleLeakDebug enables or disables the reporting of messages that
+// are not closed before they are garbage collected, which leaves their message
+// handle to be deleted by a finalizer. While enabled the stack trace of where each
+// message was created is recorded, so it adds a noticeable cost to every message
+// and is intended only for finding leaks during development.
+func SetMessageHandleLeakDebug(enabled bool) {
+	leakDebugEnabled.Store(enabled)
+}
+
+// SetMessageHandleLeakReporter sets the function that is called with the stack
+// trace of where a message was created, when leak debugging is enabled and the
+// message is garbage collected without having been closed. The reporter is
+// called from the finalizer goroutine, so must not block.
+//
+// By default the stack trace is printed to the console, which can be restored by
+// specifying a nil reporter.
+func SetMessageHandleLeakReporter(reporter func(stack string)) {
+
+	leakReporterLock.Lock()
+	defer leakReporterLock.Unlock()
+
+	if reporter == nil {
+		reporter = printLeakedMessageHandle
+	}
+	leakReporter = reporter
+}
+
+// printLeakedMessageHandle is the default reporter for leaked messages.
+func printLeakedMessageHandle(stack string) {
+	fmt.Println("Message was garbage collected without being closed. Created at:\n" + stack)
+}
+
+// newMessageHandleTracker takes ownership of the specified message handle, and
+// sets a finalizer as a safety net to delete the handle if the message is not
+// closed before it is garbage collected, to reduce/prevent memory leaks.
+func newMessageHandleTracker(msgHandle *ibmmq.MQMessageHandle, ctxLock *sync.Mutex) *messageHandleTracker {
+
+	tracker := &messageHandleTracker{
+		msgHandle: msgHandle,
+		ctxLock:   ctxLock,
+	}
+
+	if leakDebugEnabled.Load() {
+		tracker.stack = string(debug.Stack())
+	}
+
+	runtime.SetFinalizer(tracker, func(tracker *messageHandleTracker) {
+
+		if tracker.stack != "" {
+			leakReporterLock.Lock()
+			reporter := leakReporter
+			leakReporterLock.Unlock()
+
+			reporter(tracker.stack)
+		}
+
+		tracker.release("DltMH finalizer")
+	})
+
+	return tracker
+}
+
+// close deletes the message handle straight away, rather than leaving it for
+// the finalizer. It is safe to call more than once.
+func (tracker *messageHandleTracker) close() {
+
+	// The finalizer is no longer needed, which avoids the garbage collector
+	// having to contend for the context lock later on.
+	runtime.SetFinalizer(tracker, nil)
+
+	tracker.release("DltMH")
+}
+
+// release deletes the message handle if that hasn't already been done.
+func (tracker *messageHandleTracker) release(errPrefix string) {
+
+	tracker.ctxLock.Lock()
+	defer tracker.ctxLock.Unlock()
+
+	if tracker.released {
+		return
+	}
+	tracker.released = true
+
+	dmho := ibmmq.NewMQDMHO()
+	err := tracker.msgHandle.DltMH(dmho)
+	if err != nil {
+
+		mqret := err.(*ibmmq.MQReturn)
+
+		if mqret.MQRC == ibmmq.MQRC_HCONN_ERROR {
+			// Expected if the connection is closed before the handle is deleted
+			// (at which point it should get tidied up automatically by the connection)
+		} else {
+			fmt.Println(errPrefix, err)
+		}
+
+	}
+
+}
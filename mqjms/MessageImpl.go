@@ -31,11 +31,27 @@ const MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE string = "1056	"
 // MessageImpl contains the IBM MQ specific attributes that are
 // common to all types of message.
 type MessageImpl struct {
-	mqmd      *ibmmq.MQMD
-	msgHandle *ibmmq.MQMessageHandle
-	ctxLock   *sync.Mutex
+	mqmd          *ibmmq.MQMD
+	msgHandle     *ibmmq.MQMessageHandle
+	handleTracker *messageHandleTracker // Deletes the msgHandle when the message is closed
+	ctxLock       *sync.Mutex
+	destination   jms20subset.Destination
+	dlh           *ibmmq.MQDLH // Dead-letter header, if the message was received from a dead-letter queue
 }
 
+// MessageImpl_PROPERTY_JMS_TYPE is the name of the message property that carries
+// the JMSType, which is stored in the mcd folder of the MQRFH2 header in the same
+// way as the IBM MQ classes for JMS.
+const MessageImpl_PROPERTY_JMS_TYPE string = "mcd.Type"
+
+// MessageImpl_PROPERTY_CODEC is the name of the message property that records
+// the name of the Codec that was used to encode the message body.
+const MessageImpl_PROPERTY_CODEC string = "mqjms_Codec"
+
+// MessageImpl_PROPERTY_JMS_DESTINATION is the name of the message property in
+// which the IBM MQ classes for JMS store the JMSDestination as a queue URI.
+const MessageImpl_PROPERTY_JMS_DESTINATION string = "jms.Dst"
+
 // GetJMSDeliveryMode extracts the persistence setting from this message
 // and returns it in the JMS delivery mode format.
 func (msg *MessageImpl) GetJMSDeliveryMode() int {
@@ -71,6 +87,65 @@ func (msg *MessageImpl) GetJMSPriority() int {
 	return pri
 }
 
+// SetJMSType stores the message type in a message property so that it can be
+// read by both Golang and Java applications.
+func (msg *MessageImpl) SetJMSType(jmsType string) jms20subset.JMSException {
+
+	if jmsType == "" {
+
+		// Removing a type that isn't set is not an error.
+		retErr := msg.deletePropertyValue(MessageImpl_PROPERTY_JMS_TYPE)
+		if retErr != nil && retErr.GetErrorCode() == strconv.Itoa(int(ibmmq.MQRC_PROPERTY_NOT_AVAILABLE)) {
+			retErr = nil
+		}
+		return retErr
+	}
+
+	return msg.setPropertyValue(MessageImpl_PROPERTY_JMS_TYPE, jmsType)
+}
+
+// GetJMSType returns the message type from the message property in which it is
+// stored, or an empty string if it is not set.
+func (msg *MessageImpl) GetJMSType() string {
+
+	jmsType := ""
+
+	value, retErr := msg.getPropertyValue(MessageImpl_PROPERTY_JMS_TYPE)
+	if retErr == nil {
+		if valueStr, ok := value.(string); ok {
+			jmsType = valueStr
+		}
+	}
+
+	return jmsType
+}
+
+// GetJMSDestination returns the Destination to which the message was sent.
+//
+// This is taken from the destination URI that is stored with the message when it
+// is sent, by this library or the IBM MQ classes for JMS, and otherwise it is the
+// Destination that was used to send or receive the message.
+func (msg *MessageImpl) GetJMSDestination() jms20subset.Destination {
+
+	value, retErr := msg.getPropertyValue(MessageImpl_PROPERTY_JMS_DESTINATION)
+	if retErr == nil {
+		if uri, ok := value.(string); ok && strings.HasPrefix(uri, queueURIPrefix) {
+			if queue, err := parseQueueURI(uri); err == nil {
+				return queue
+			}
+		}
+	}
+
+	return msg.destination
+}
+
+// GetJMSDeliveryTime returns the earliest time at which the message can be
+// delivered, which is the same as the send timestamp because delivery delay
+// is not supported.
+func (msg *MessageImpl) GetJMSDeliveryTime() int64 {
+	return msg.GetJMSTimestamp()
+}
+
 // GetJMSMessageID extracts the message ID from the native MQ message descriptor.
 func (msg *MessageImpl) GetJMSMessageID() string {
 	msgIDStr := ""
@@ -85,6 +160,20 @@ func (msg *MessageImpl) GetJMSMessageID() string {
 	return msgIDStr
 }
 
+// GetJMSMessageIDAsBytes returns a copy of the bytes in the message ID field
+// of the native MQ message descriptor.
+func (msg *MessageImpl) GetJMSMessageIDAsBytes() []byte {
+	var msgIDBytes []byte
+
+	// Note that if there is no MQMD then there is no messageID to return.
+	if msg.mqmd != nil && msg.mqmd.MsgId != nil {
+		msgIDBytes = make([]byte, len(msg.mqmd.MsgId))
+		copy(msgIDBytes, msg.mqmd.MsgId)
+	}
+
+	return msgIDBytes
+}
+
 // SetJMSReplyTo uses the specified Destination object to configure the reply
 // attributes of the native MQ message fields.
 func (msg *MessageImpl) SetJMSReplyTo(dest jms20subset.Destination) jms20subset.JMSException {
@@ -100,6 +189,7 @@ func (msg *MessageImpl) SetJMSReplyTo(dest jms20subset.Destination) jms20subset.
 
 		// Save the queue information into the MQMD so that it can be transmitted.
 		msg.mqmd.ReplyToQ = typedDest.queueName
+		msg.mqmd.ReplyToQMgr = typedDest.qMgrName
 
 	default:
 		// This "should never happen"(!) apart from in situations where we are
@@ -122,11 +212,12 @@ func (msg *MessageImpl) GetJMSReplyTo() jms20subset.Destination {
 	// destination.
 	if msg.mqmd != nil && msg.mqmd.ReplyToQ != "" {
 		replyQ := strings.TrimSpace(msg.mqmd.ReplyToQ)
+		replyQMgr := strings.TrimSpace(msg.mqmd.ReplyToQMgr)
 
 		// Create the Destination object and populate it to be returned.
-		replyDest = QueueImpl{
-			queueName: replyQ,
-		}
+		replyQueue := newQueueImpl(replyQ)
+		replyQueue.qMgrName = replyQMgr
+		replyDest = replyQueue
 	}
 
 	return replyDest
@@ -223,6 +314,44 @@ func (msg *MessageImpl) GetJMSCorrelationID() string {
 	return correlID
 }
 
+// SetJMSCorrelationIDAsBytes stores the specified bytes in the correlation ID
+// field of the native MQ message descriptor without any conversion.
+func (msg *MessageImpl) SetJMSCorrelationIDAsBytes(correlID []byte) jms20subset.JMSException {
+
+	if len(correlID) > int(ibmmq.MQ_CORREL_ID_LENGTH) {
+		return jms20subset.CreateJMSException("CorrelationID must not be longer than "+
+			strconv.Itoa(int(ibmmq.MQ_CORREL_ID_LENGTH))+" bytes", "InvalidCorrelationID", nil)
+	}
+
+	// The CorrelID is carried in the MQ message descriptor, so if there isn't
+	// one already associated with this message then we need to create one.
+	if msg.mqmd == nil {
+		msg.mqmd = ibmmq.NewMQMD()
+	}
+
+	// Replace the whole field so that any bytes from a previous value are
+	// cleared, and shorter values are padded with zeros.
+	correlIDBytes := make([]byte, ibmmq.MQ_CORREL_ID_LENGTH)
+	copy(correlIDBytes, correlID)
+	msg.mqmd.CorrelId = correlIDBytes
+
+	return nil
+}
+
+// GetJMSCorrelationIDAsBytes returns a copy of the bytes in the correlation ID
+// field of the native MQ message descriptor.
+func (msg *MessageImpl) GetJMSCorrelationIDAsBytes() []byte {
+	var correlIDBytes []byte
+
+	// Note that if there is no MQMD then there is no correlID stored.
+	if msg.mqmd != nil && msg.mqmd.CorrelId != nil {
+		correlIDBytes = make([]byte, len(msg.mqmd.CorrelId))
+		copy(correlIDBytes, msg.mqmd.CorrelId)
+	}
+
+	return correlIDBytes
+}
+
 // GetJMSTimestamp retrieves the timestamp at which the message was sent from
 // the native MQ message descriptor fields.
 func (msg *MessageImpl) GetJMSTimestamp() int64 {
@@ -431,6 +560,30 @@ func (msg *MessageImpl) setSpecialIntPropertyValue(name string, value int) (bool
 	case "JMS_IBM_Report_COD":
 		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the COD value
 
+	case "JMS_IBM_Report_Exception":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the exception value
+
+	case "JMS_IBM_Report_Expiration":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the expiration value
+
+	case "JMS_IBM_Report_PAN":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the PAN value
+
+	case "JMS_IBM_Report_NAN":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the NAN value
+
+	case "JMS_IBM_Report_Pass_Msg_ID":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the pass message ID value
+
+	case "JMS_IBM_Report_Pass_Correl_ID":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the pass correlation ID value
+
+	case "JMS_IBM_Report_Discard_Msg":
+		msg.mqmd.Report |= int32(value) // bitwise merge (OR) the discard value
+
+	case "JMS_IBM_MQMD_Report":
+		msg.mqmd.Report = int32(value) // replaces all of the report options
+
 	case "JMS_IBM_Character_Set":
 		msg.mqmd.CodedCharSetId = int32(value)
 
@@ -507,6 +660,86 @@ func (msg *MessageImpl) getSpecialPropertyValue(name string) (bool, interface{},
 			value = msg.mqmd.Report & ibmmq.MQRO_COD_WITH_FULL_DATA // bitwise retrieve just the COD data
 		}
 
+	case "JMS_IBM_Report_Exception":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_EXCEPTION_WITH_FULL_DATA // bitwise retrieve just the exception data
+		}
+
+	case "JMS_IBM_Report_Expiration":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_EXPIRATION_WITH_FULL_DATA // bitwise retrieve just the expiration data
+		}
+
+	case "JMS_IBM_Report_PAN":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_PAN // bitwise retrieve just the PAN flag
+		}
+
+	case "JMS_IBM_Report_NAN":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_NAN // bitwise retrieve just the NAN flag
+		}
+
+	case "JMS_IBM_Report_Pass_Msg_ID":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_PASS_MSG_ID // bitwise retrieve just the pass message ID flag
+		}
+
+	case "JMS_IBM_Report_Pass_Correl_ID":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_PASS_CORREL_ID // bitwise retrieve just the pass correlation ID flag
+		}
+
+	case "JMS_IBM_Report_Discard_Msg":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report & ibmmq.MQRO_DISCARD_MSG // bitwise retrieve just the discard flag
+		}
+
+	case "JMS_IBM_MQMD_Report":
+		if msg.mqmd != nil && msg.mqmd.Report != ibmmq.MQRO_NONE {
+			value = msg.mqmd.Report
+		}
+
+	case "JMS_IBM_DLH_Reason":
+		if msg.dlh != nil {
+			value = msg.dlh.Reason
+		}
+
+	case "JMS_IBM_DLH_DestQName":
+		if msg.dlh != nil {
+			value = msg.dlh.DestQName
+		}
+
+	case "JMS_IBM_DLH_DestQMgrName":
+		if msg.dlh != nil {
+			value = msg.dlh.DestQMgrName
+		}
+
+	case "JMS_IBM_DLH_Format":
+		if msg.dlh != nil && msg.dlh.Format != ibmmq.MQFMT_NONE {
+			value = msg.dlh.Format
+		}
+
+	case "JMS_IBM_DLH_PutApplType":
+		if msg.dlh != nil {
+			value = msg.dlh.PutApplType
+		}
+
+	case "JMS_IBM_DLH_PutApplName":
+		if msg.dlh != nil {
+			value = msg.dlh.PutApplName
+		}
+
+	case "JMS_IBM_DLH_PutDate":
+		if msg.dlh != nil {
+			value = msg.dlh.PutDate
+		}
+
+	case "JMS_IBM_DLH_PutTime":
+		if msg.dlh != nil {
+			value = msg.dlh.PutTime
+		}
+
 	case "JMSXAppID":
 		if msg.mqmd != nil {
 			value = msg.mqmd.PutApplName
@@ -619,6 +852,15 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 			switch valueTyped := value.(type) {
 			case string:
 				valueStrPtr = &valueTyped
+			case int8:
+				valueStr := strconv.FormatInt(int64(valueTyped), 10)
+				valueStrPtr = &valueStr
+			case int16:
+				valueStr := strconv.FormatInt(int64(valueTyped), 10)
+				valueStrPtr = &valueStr
+			case int32:
+				valueStr := strconv.FormatInt(int64(valueTyped), 10)
+				valueStrPtr = &valueStr
 			case int64:
 				valueStr := strconv.FormatInt(valueTyped, 10)
 				valueStrPtr = &valueStr
@@ -632,6 +874,9 @@ func (msg *MessageImpl) GetStringProperty(name string) (*string, jms20subset.JMS
 			case float64:
 				valueStr := fmt.Sprintf("%g", valueTyped)
 				valueStrPtr = &valueStr
+			case float32:
+				valueStr := strconv.FormatFloat(float64(valueTyped), 'g', -1, 32)
+				valueStrPtr = &valueStr
 			default:
 				retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
 					MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, parseErr)
@@ -726,6 +971,10 @@ func (msg *MessageImpl) GetIntProperty(name string) (int, jms20subset.JMSExcepti
 		switch valueTyped := value.(type) {
 		case int:
 			valueRet = valueTyped
+		case int8:
+			valueRet = int(valueTyped)
+		case int16:
+			valueRet = int(valueTyped)
 		case int32:
 			valueRet = int(valueTyped)
 		case int64:
@@ -824,6 +1073,8 @@ func (msg *MessageImpl) GetDoubleProperty(name string) (float64, jms20subset.JMS
 		switch valueTyped := value.(type) {
 		case float64:
 			valueRet = valueTyped
+		case float32:
+			valueRet = float64(valueTyped)
 		case string:
 			valueRet, parseErr = strconv.ParseFloat(valueTyped, 64)
 			if parseErr != nil {
@@ -994,6 +1245,320 @@ func (msg *MessageImpl) GetBooleanProperty(name string) (bool, jms20subset.JMSEx
 	return valueRet, retErr
 }
 
+// SetByteProperty enables an application to set a byte-type (int8) message property.
+func (msg *MessageImpl) SetByteProperty(name string, value int8) jms20subset.JMSException {
+	return msg.setPropertyValue(name, value)
+}
+
+// GetByteProperty returns the byte (int8) value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetByteProperty(name string) (int8, jms20subset.JMSException) {
+
+	var valueRet int8
+
+	value, retErr := msg.getPropertyValue(name)
+
+	if retErr == nil && value != nil {
+
+		var parseErr error
+
+		switch valueTyped := value.(type) {
+		case int8:
+			valueRet = valueTyped
+		case string:
+			var parsed int64
+			parsed, parseErr = strconv.ParseInt(valueTyped, 10, 8)
+			valueRet = int8(parsed)
+		default:
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+		}
+
+		if parseErr != nil {
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+		}
+	}
+
+	return valueRet, retErr
+}
+
+// SetShortProperty enables an application to set a short-type (int16) message property.
+func (msg *MessageImpl) SetShortProperty(name string, value int16) jms20subset.JMSException {
+	return msg.setPropertyValue(name, value)
+}
+
+// GetShortProperty returns the short (int16) value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetShortProperty(name string) (int16, jms20subset.JMSException) {
+
+	var valueRet int16
+
+	value, retErr := msg.getPropertyValue(name)
+
+	if retErr == nil && value != nil {
+
+		var parseErr error
+
+		switch valueTyped := value.(type) {
+		case int8:
+			valueRet = int16(valueTyped)
+		case int16:
+			valueRet = valueTyped
+		case string:
+			var parsed int64
+			parsed, parseErr = strconv.ParseInt(valueTyped, 10, 16)
+			valueRet = int16(parsed)
+		default:
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+		}
+
+		if parseErr != nil {
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+		}
+	}
+
+	return valueRet, retErr
+}
+
+// SetLongProperty enables an application to set a long-type (int64) message property.
+func (msg *MessageImpl) SetLongProperty(name string, value int64) jms20subset.JMSException {
+	return msg.setPropertyValue(name, value)
+}
+
+// GetLongProperty returns the long (int64) value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetLongProperty(name string) (int64, jms20subset.JMSException) {
+
+	var valueRet int64
+
+	value, retErr := msg.getPropertyValue(name)
+
+	if retErr == nil && value != nil {
+
+		var parseErr error
+
+		switch valueTyped := value.(type) {
+		case int8:
+			valueRet = int64(valueTyped)
+		case int16:
+			valueRet = int64(valueTyped)
+		case int32:
+			valueRet = int64(valueTyped)
+		case int:
+			valueRet = int64(valueTyped)
+		case int64:
+			valueRet = valueTyped
+		case string:
+			valueRet, parseErr = strconv.ParseInt(valueTyped, 10, 64)
+		default:
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+		}
+
+		if parseErr != nil {
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+		}
+	}
+
+	return valueRet, retErr
+}
+
+// SetFloatProperty enables an application to set a float-type (float32) message property.
+func (msg *MessageImpl) SetFloatProperty(name string, value float32) jms20subset.JMSException {
+	return msg.setPropertyValue(name, value)
+}
+
+// GetFloatProperty returns the float (float32) value of a named message property.
+// Returns 0 if the named property is not set.
+func (msg *MessageImpl) GetFloatProperty(name string) (float32, jms20subset.JMSException) {
+
+	var valueRet float32
+
+	value, retErr := msg.getPropertyValue(name)
+
+	if retErr == nil && value != nil {
+
+		var parseErr error
+
+		switch valueTyped := value.(type) {
+		case float32:
+			valueRet = valueTyped
+		case string:
+			var parsed float64
+			parsed, parseErr = strconv.ParseFloat(valueTyped, 32)
+			valueRet = float32(parsed)
+		default:
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+		}
+
+		if parseErr != nil {
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_FAILED_REASON,
+				MessageImpl_PROPERTY_CONVERT_FAILED_CODE, parseErr)
+		}
+	}
+
+	return valueRet, retErr
+}
+
+// SetBytesProperty enables an application to set a byte array message property.
+// A nil value unsets the property.
+func (msg *MessageImpl) SetBytesProperty(name string, value []byte) jms20subset.JMSException {
+
+	if value == nil {
+		return msg.deletePropertyValue(name)
+	}
+
+	return msg.setPropertyValue(name, value)
+}
+
+// GetBytesProperty returns the byte array value of a named message property.
+// Returns nil if the named property is not set.
+func (msg *MessageImpl) GetBytesProperty(name string) ([]byte, jms20subset.JMSException) {
+
+	var valueRet []byte
+
+	value, retErr := msg.getPropertyValue(name)
+
+	if retErr == nil && value != nil {
+
+		// Byte arrays cannot be converted to or from any other type.
+		switch valueTyped := value.(type) {
+		case []byte:
+			valueRet = valueTyped
+		default:
+			retErr = jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+				MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+		}
+	}
+
+	return valueRet, retErr
+}
+
+// SetObjectProperty enables an application to set a message property using any
+// of the supported types. A nil value unsets the property.
+func (msg *MessageImpl) SetObjectProperty(name string, value interface{}) jms20subset.JMSException {
+
+	// Delegate to the typed functions where they have special handling, for
+	// example for properties that are stored in the MQMD.
+	switch valueTyped := value.(type) {
+	case nil:
+		return msg.deletePropertyValue(name)
+	case string:
+		return msg.SetStringProperty(name, &valueTyped)
+	case *string:
+		return msg.SetStringProperty(name, valueTyped)
+	case int:
+		return msg.SetIntProperty(name, valueTyped)
+	case bool:
+		return msg.SetBooleanProperty(name, valueTyped)
+	case []byte:
+		return msg.SetBytesProperty(name, valueTyped)
+	case int8, int16, int32, int64, float32, float64:
+		return msg.setPropertyValue(name, valueTyped)
+	default:
+		return jms20subset.CreateJMSException(MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_REASON,
+			MessageImpl_PROPERTY_CONVERT_NOTSUPPORTED_CODE, nil)
+	}
+}
+
+// GetObjectProperty returns the value of a named message property in the type
+// with which it was set.
+// Returns nil if the named property is not set.
+func (msg *MessageImpl) GetObjectProperty(name string) (interface{}, jms20subset.JMSException) {
+	return msg.getPropertyValue(name)
+}
+
+// setPropertyValue is an internal helper that stores a user property of any of the
+// types supported by the message handle, which selects the matching MQTYPE.
+func (msg *MessageImpl) setPropertyValue(name string, value interface{}) jms20subset.JMSException {
+	var retErr jms20subset.JMSException
+
+	smpo := ibmmq.NewMQSMPO()
+	pd := ibmmq.NewMQPD()
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	msg.ctxLock.Lock()
+	defer msg.ctxLock.Unlock()
+
+	linkedErr := msg.msgHandle.SetMP(smpo, name, pd, value)
+
+	if linkedErr != nil {
+		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+	}
+
+	return retErr
+}
+
+// deletePropertyValue is an internal helper that removes a user property.
+func (msg *MessageImpl) deletePropertyValue(name string) jms20subset.JMSException {
+	var retErr jms20subset.JMSException
+
+	dmpo := ibmmq.NewMQDMPO()
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	msg.ctxLock.Lock()
+	defer msg.ctxLock.Unlock()
+
+	linkedErr := msg.msgHandle.DltMP(dmpo, name)
+
+	if linkedErr != nil {
+		rcInt := int(linkedErr.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		retErr = jms20subset.CreateJMSException(reason, errCode, linkedErr)
+	}
+
+	return retErr
+}
+
+// getPropertyValue is an internal helper that returns the value of a special or
+// user property in its native type, or nil if the property is not set.
+func (msg *MessageImpl) getPropertyValue(name string) (interface{}, jms20subset.JMSException) {
+
+	// Check first if this is a special property
+	isSpecialProp, value, err := msg.getSpecialPropertyValue(name)
+
+	if !isSpecialProp {
+
+		impo := ibmmq.NewMQIMPO()
+		pd := ibmmq.NewMQPD()
+
+		// Lock the context while we are making calls to the queue manager so that it
+		// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+		msg.ctxLock.Lock()
+		defer msg.ctxLock.Unlock()
+
+		// If not then look for a user property
+		_, value, err = msg.msgHandle.InqMP(impo, pd, name)
+	}
+
+	if err != nil {
+
+		mqret := err.(*ibmmq.MQReturn)
+		if mqret.MQRC == ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
+			// This indicates that the requested property does not exist.
+			return nil, nil
+		}
+
+		rcInt := int(mqret.MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		return nil, jms20subset.CreateJMSException(reason, errCode, mqret)
+	}
+
+	return value, nil
+}
+
 // PropertyExists returns true if the named message property exists on this message.
 func (msg *MessageImpl) PropertyExists(name string) (bool, jms20subset.JMSException) {
 
@@ -1097,3 +1662,16 @@ func (msg *MessageImpl) ClearProperties() jms20subset.JMSException {
 	return jmsErr
 
 }
+
+// Close deletes the MQ message handle that holds the properties of this message,
+// so that its resources are released straight away instead of when the message
+// is garbage collected. The message must not be used after it has been closed.
+//
+// It is safe to call Close more than once.
+func (msg *MessageImpl) Close() {
+
+	if msg.handleTracker != nil {
+		msg.handleTracker.close()
+	}
+
+}
@@ -10,7 +10,9 @@
 package mqjms
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"strconv"
 	"strings"
@@ -26,6 +28,11 @@ type ProducerImpl struct {
 	deliveryMode int
 	timeToLive   int
 	priority     int
+	compression  string
+
+	// contextSource is the queue that a forwarded message was received from, whose
+	// saved context is passed on to the new message.
+	contextSource *ibmmq.MQObject
 }
 
 // SendString sends a TextMessage with the specified body to the specified Destination
@@ -35,6 +42,7 @@ func (producer ProducerImpl) SendString(dest jms20subset.Destination, bodyStr st
 	// This is essentially just a helper method that avoids the application having
 	// to create its own TextMessage object.
 	msg := producer.ctx.CreateTextMessage()
+	defer msg.Close() // Only used for this send, so release it straight away
 	msg.SetText(bodyStr)
 
 	return producer.Send(dest, msg)
@@ -48,15 +56,203 @@ func (producer ProducerImpl) SendBytes(dest jms20subset.Destination, body []byte
 	// This is essentially just a helper method that avoids the application having
 	// to create its own TextMessage object.
 	msg := producer.ctx.CreateBytesMessage()
+	defer msg.Close() // Only used for this send, so release it straight away
 	msg.WriteBytes(body)
 
 	return producer.Send(dest, msg)
 
 }
 
+// streamSegmentSize is the size of each segment when a stream is sent, which is
+// well within the default MAXMSGL of 4MB for a queue.
+const streamSegmentSize = 1024 * 1024
+
+// SendStream sends the content of the reader to the specified Destination as a
+// single logical message, which is split into segments so that the whole of the
+// content never needs to be held in memory.
+//
+// The segments are put under syncpoint so that they only become available to
+// consumers once they have all been sent. If this context is not transacted then
+// they are committed when the end of the reader is reached, otherwise they are
+// committed along with the rest of the transaction.
+//
+// Streams cannot be compressed or protected, so an exception is returned if this
+// producer has compression enabled, or if a protection policy applies to the
+// destination.
+func (producer ProducerImpl) SendStream(dest jms20subset.Destination, reader io.Reader) jms20subset.JMSException {
+
+	if producer.compression != jms20subset.Compression_NONE {
+		return jms20subset.CreateJMSException("CompressionNotSupported", "CompressionNotSupported",
+			errors.New("Streams cannot be compressed"))
+	}
+
+	protectionErr := checkStreamProtection(producer.ctx, dest)
+	if protectionErr != nil {
+		return protectionErr
+	}
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	producer.ctx.ctxLock.Lock()
+	defer producer.ctx.ctxLock.Unlock()
+
+	// Segments must all be put using the same object handle, so the queue
+	// is opened rather than using MQPUT1.
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = dest.GetDestinationName()
+
+	if queue, ok := dest.(jms20subset.Queue); ok {
+		mqod.ObjectQMgrName = queue.GetQueueManagerName()
+	}
+
+	qObject, err := producer.ctx.qMgr.Open(mqod, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		return jms20subset.CreateJMSException(reason, errCode, err)
+	}
+	defer qObject.Close(0)
+
+	// Segmentation requires version 2 of the MQMD, and with logical order the
+	// queue manager assigns the group ID and offset of each segment.
+	putmqmd := ibmmq.NewMQMD()
+	putmqmd.Version = ibmmq.MQMD_VERSION_2
+	putmqmd.Format = ibmmq.MQFMT_NONE
+	producer.applyDeliveryOptions(dest, putmqmd)
+
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_NEW_MSG_ID | ibmmq.MQPMO_LOGICAL_ORDER |
+		ibmmq.MQPMO_FAIL_IF_QUIESCING
+
+	// Read one segment ahead so that we know which segment is the last one.
+	segment, readErr := readSegment(reader)
+
+	for readErr == nil {
+
+		var nextSegment []byte
+		nextSegment, readErr = readSegment(reader)
+		if readErr != nil {
+			break
+		}
+
+		putmqmd.MsgFlags = ibmmq.MQMF_LAST_SEGMENT
+		if len(nextSegment) > 0 {
+			putmqmd.MsgFlags = ibmmq.MQMF_SEGMENT
+		}
+
+		err = qObject.Put(putmqmd, pmo, segment)
+		if err != nil || len(nextSegment) == 0 {
+			break
+		}
+
+		segment = nextSegment
+	}
+
+	if err == nil && readErr == nil && producer.ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED {
+		err = producer.ctx.qMgr.Cmit()
+	}
+
+	if err != nil || readErr != nil {
+
+		// Don't leave a partial stream behind if we control the unit of work.
+		if producer.ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED {
+			producer.ctx.qMgr.Back()
+		}
+
+		if readErr != nil {
+			return jms20subset.CreateJMSException("StreamReadFailed", "StreamReadFailed", readErr)
+		}
+
+		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		return jms20subset.CreateJMSException(reason, errCode, err)
+	}
+
+	return nil
+}
+
+// readSegment reads up to one segment of data from the reader, returning an empty
+// slice once the end of the reader has been reached.
+func readSegment(reader io.Reader) ([]byte, error) {
+
+	segment := make([]byte, streamSegmentSize)
+
+	n, err := io.ReadFull(reader, segment)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+
+	return segment[:n], err
+}
+
+// CreateGroupProducer creates a producer that sends messages to the specified
+// Destination as message groups, using the message options that are defined on
+// this JMSProducer.
+func (producer ProducerImpl) CreateGroupProducer(dest jms20subset.Destination) (jms20subset.GroupProducer, jms20subset.JMSException) {
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	producer.ctx.ctxLock.Lock()
+	defer producer.ctx.ctxLock.Unlock()
+
+	// The messages in a group must all be put using the same object handle, so
+	// the queue is opened rather than using MQPUT1.
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = dest.GetDestinationName()
+
+	if queue, ok := dest.(jms20subset.Queue); ok {
+		mqod.ObjectQMgrName = queue.GetQueueManagerName()
+	}
+
+	qObject, err := producer.ctx.qMgr.Open(mqod, ibmmq.MQOO_OUTPUT|ibmmq.MQOO_FAIL_IF_QUIESCING)
+	if err != nil {
+		rcInt := int(err.(*ibmmq.MQReturn).MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		return nil, jms20subset.CreateJMSException(reason, errCode, err)
+	}
+
+	groupProducer := &GroupProducerImpl{
+		producer: producer,
+		dest:     dest,
+		qObject:  qObject,
+	}
+
+	return groupProducer, nil
+}
+
 // Send a message to the specified IBM MQ queue, using the message options
 // that are defined on this JMSProducer.
 func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.Message) jms20subset.JMSException {
+	return producer.sendInternal(dest, msg, nil, false)
+}
+
+// forwardMessage sends a message that was received by the consumer on to another
+// queue, in the same way as the runmqdlq handler. The message keeps its message ID
+// along with the persistence, priority and remaining expiry that it was received
+// with, and all of its context is passed from the queue it was received from.
+//
+// Passing context requires passall authority on the destination. Without it the
+// message is sent with default context instead, but still keeps its message ID.
+func (producer ProducerImpl) forwardMessage(dest jms20subset.Destination, msg jms20subset.Message,
+	consumer jms20subset.JMSConsumer) jms20subset.JMSException {
+
+	if consumerImpl, ok := consumer.(ConsumerImpl); ok {
+		producer.contextSource = &consumerImpl.qObject
+	}
+
+	return producer.sendInternal(dest, msg, nil, false)
+}
+
+// sendInternal provides the common logic for sending a message, either on its own
+// using MQPUT1 or as part of a message group using the queue that has been opened
+// by the GroupProducer.
+func (producer ProducerImpl) sendInternal(dest jms20subset.Destination, msg jms20subset.Message,
+	group *GroupProducerImpl, lastInGroup bool) jms20subset.JMSException {
 
 	// Lock the context while we are making calls to the queue manager so that it
 	// doesn't conflict with the finalizer we use (below) to delete unused MessageHandles.
@@ -74,6 +270,12 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	mqod.ObjectType = ibmmq.MQOT_Q
 	mqod.ObjectName = dest.GetDestinationName()
 
+	// If the queue is hosted on a remote queue manager then tell MQ where to
+	// route the message.
+	if queue, ok := dest.(jms20subset.Queue); ok {
+		mqod.ObjectQMgrName = queue.GetQueueManagerName()
+	}
+
 	// Calculate the syncpoint value
 	syncpointSetting := ibmmq.MQPMO_NO_SYNCPOINT
 	if producer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
@@ -81,8 +283,14 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	}
 
 	// Configure the put message options, including asking MQ to allocate a
-	// unique message ID
-	pmo.Options = syncpointSetting | ibmmq.MQPMO_NEW_MSG_ID
+	// unique message ID unless a received message is being forwarded, in which
+	// case it keeps its ID and the context it was received with.
+	if producer.contextSource != nil {
+		pmo.Options = syncpointSetting | ibmmq.MQPMO_PASS_ALL_CONTEXT
+		pmo.Context = producer.contextSource
+	} else {
+		pmo.Options = syncpointSetting | ibmmq.MQPMO_NEW_MSG_ID
+	}
 
 	// Is async put has been requested then apply the appropriate PMO option
 	if dest.GetPutAsyncAllowed() == jms20subset.Destination_PUT_ASYNC_ALLOWED_ENABLED {
@@ -90,6 +298,12 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 	}
 
 	var buffer []byte
+	var msgHandle *ibmmq.MQMessageHandle
+
+	// A report message is sent in the same way as the BytesMessage it contains.
+	if reportMsg, ok := msg.(*ReportMessageImpl); ok {
+		msg = &reportMsg.BytesMessageImpl
+	}
 
 	// We have a "Message" object and can use a switch to safely convert it
 	// to the implementation type in order to extract generic MQ message
@@ -104,9 +318,11 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 
 		// Pass up the handle containing the message properties
 		pmo.OriginalMsgHandle = *typedMsg.msgHandle
+		msgHandle = typedMsg.msgHandle
 
 		// Store the Put MQMD so that we can later retrieve "out" fields like MsgId
 		typedMsg.mqmd = putmqmd
+		typedMsg.destination = dest
 
 		// Set up this MQ message to contain the string from the JMS message.
 		trimmedFormat := strings.TrimSpace(putmqmd.Format)
@@ -114,9 +330,15 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 			putmqmd.Format = ibmmq.MQFMT_STRING
 		}
 
+		// Convert the text into the character set of the message, if one has been set.
 		msgStr := typedMsg.GetText()
 		if msgStr != nil {
-			buffer = []byte(*msgStr)
+			var encodeErr error
+			buffer, encodeErr = encodeText(*msgStr, putmqmd.CodedCharSetId, putmqmd.Encoding)
+			if encodeErr != nil {
+				return jms20subset.CreateJMSException("CharacterSetConversionFailed",
+					"CharacterSetConversionFailed", encodeErr)
+			}
 		}
 
 	case *BytesMessageImpl:
@@ -129,9 +351,11 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 
 		// Pass up the handle containing the message properties
 		pmo.OriginalMsgHandle = *typedMsg.msgHandle
+		msgHandle = typedMsg.msgHandle
 
 		// Store the Put MQMD so that we can later retrieve "out" fields like MsgId
 		typedMsg.mqmd = putmqmd
+		typedMsg.destination = dest
 
 		// Set up this MQ message to contain the bytes from the JMS message.
 		buffer = *typedMsg.ReadBytes()
@@ -142,29 +366,122 @@ func (producer ProducerImpl) Send(dest jms20subset.Destination, msg jms20subset.
 		log.Fatal(jms20subset.CreateJMSException("UnexpectedMessageType", "UnexpectedMessageType-send1", nil))
 	}
 
-	// Convert the JMS persistence into the equivalent MQ message descriptor
-	// attribute.
-	if producer.deliveryMode == jms20subset.DeliveryMode_NON_PERSISTENT {
-		putmqmd.Persistence = ibmmq.MQPER_NOT_PERSISTENT
-	} else {
-		putmqmd.Persistence = ibmmq.MQPER_PERSISTENT
+	// Record the destination with the message as a queue URI, in the same way as the
+	// IBM MQ classes for JMS, so that it is returned by GetJMSDestination when the
+	// message is received.
+	if queue, ok := dest.(jms20subset.Queue); ok {
+		dstErr := msgHandle.SetMP(ibmmq.NewMQSMPO(), MessageImpl_PROPERTY_JMS_DESTINATION, ibmmq.NewMQPD(),
+			queueURIPrefix+queue.GetQueueManagerName()+"/"+queue.GetQueueName())
+		if dstErr != nil {
+			rcInt := int(dstErr.(*ibmmq.MQReturn).MQRC)
+			errCode := strconv.Itoa(rcInt)
+			reason := ibmmq.MQItoString("RC", rcInt)
+			return jms20subset.CreateJMSException(reason, errCode, dstErr)
+		}
 	}
 
-	// If the producer has a TTL specified then apply it to the put MQMD so
-	// that MQ will honour it.
-	if producer.timeToLive > 0 {
-		// Note that JMS timeToLive in milliseconds, whereas MQMD Expiry expects
-		// 10ths of a second
-		putmqmd.Expiry = (int32(producer.timeToLive) / 100)
+	// Apply the delivery options of the producer, or the overrides from the destination.
+	// A forwarded message keeps the options that it was received with.
+	if producer.contextSource == nil {
+		producer.applyDeliveryOptions(dest, putmqmd)
 	}
 
-	// Convert the JMS priority into the equivalent MQ message descriptor
-	// attribute.
-	putmqmd.Priority = int32(producer.priority)
+	// Compress the body if requested, marking the message so that the consumer
+	// knows to decompress it.
+	originalFormat := putmqmd.Format
+	compressed := false
+
+	if producer.compression != jms20subset.Compression_NONE && len(buffer) > 0 {
+
+		compressedBuffer, compressErr := compressBody(producer.compression, buffer)
+		if compressErr == nil {
+			compressErr = markCompressed(msgHandle, putmqmd, producer.compression)
+		}
+
+		if compressErr != nil {
+			// Report the original failure rather than any failure to tidy up after it.
+			unmarkCompressed(msgHandle, putmqmd, originalFormat)
+			return jms20subset.CreateJMSException("CompressionFailed", "CompressionFailed", compressErr)
+		}
+
+		buffer = compressedBuffer
+		compressed = true
+	}
+
+	// Sign and/or encrypt the body and properties if a protection policy applies to
+	// this destination. This takes place after compression because encrypted data
+	// does not compress.
+	protectedFormat := putmqmd.Format
+	var protectedProperties map[string]interface{}
 
-	// Invoke the MQ command to put the message using MQPUT1 to avoid MQOPEN and MQCLOSE.
-	// Any Err that occurs will be handled below.
-	err := producer.ctx.qMgr.Put1(mqod, putmqmd, pmo, buffer)
+	if policy, ok := producer.ctx.protectionPolicies[dest.GetDestinationName()]; ok &&
+		policy.QualityOfProtection != ProtectionPolicy_QOP_NONE {
+
+		protectedBuffer, properties, protectErr := protectMessage(policy, msgHandle, putmqmd, buffer)
+		if protectErr != nil {
+			if compressed {
+				unmarkCompressed(msgHandle, putmqmd, originalFormat)
+			}
+			return jms20subset.CreateJMSException("ProtectionFailed", "ProtectionFailed", protectErr)
+		}
+
+		buffer = protectedBuffer
+		protectedProperties = properties
+	}
+
+	var err error
+
+	if group != nil {
+
+		// Messages in a group are put in logical order using the queue opened by the
+		// GroupProducer, so that the queue manager assigns the group ID and the
+		// sequence number of each message.
+		putmqmd.Version = ibmmq.MQMD_VERSION_2
+		putmqmd.GroupId = make([]byte, ibmmq.MQ_GROUP_ID_LENGTH)
+		putmqmd.MsgFlags &^= ibmmq.MQMF_MSG_IN_GROUP | ibmmq.MQMF_LAST_MSG_IN_GROUP
+		if lastInGroup {
+			putmqmd.MsgFlags |= ibmmq.MQMF_LAST_MSG_IN_GROUP
+		} else {
+			putmqmd.MsgFlags |= ibmmq.MQMF_MSG_IN_GROUP
+		}
+		pmo.Options |= ibmmq.MQPMO_LOGICAL_ORDER
+
+		err = group.qObject.Put(putmqmd, pmo, buffer)
+		if err == nil {
+			group.groupID = append([]byte{}, putmqmd.GroupId...)
+		}
+
+	} else {
+
+		// Invoke the MQ command to put the message using MQPUT1 to avoid MQOPEN and MQCLOSE.
+		// Any Err that occurs will be handled below.
+		err = producer.ctx.qMgr.Put1(mqod, putmqmd, pmo, buffer)
+
+		// If we aren't allowed to pass the context of a forwarded message then fall
+		// back to default context, which still keeps the message ID.
+		if err != nil && producer.contextSource != nil &&
+			err.(*ibmmq.MQReturn).MQRC == ibmmq.MQRC_NOT_AUTHORIZED {
+
+			pmo.Options &^= ibmmq.MQPMO_PASS_ALL_CONTEXT
+			pmo.Context = nil
+			err = producer.ctx.qMgr.Put1(mqod, putmqmd, pmo, buffer)
+		}
+	}
+
+	// Return the message to its original state so that the application sees the
+	// same properties and format that it set.
+	if protectedProperties != nil {
+		unmarkProtected(msgHandle, putmqmd, protectedProperties, protectedFormat)
+	}
+	if compressed {
+
+		// The message has already been sent, so an error is not returned as that
+		// would suggest to the application that it needs to send it again.
+		unmarkErr := unmarkCompressed(msgHandle, putmqmd, originalFormat)
+		if unmarkErr != nil {
+			fmt.Println("Unable to remove the compression marker from the message", unmarkErr)
+		}
+	}
 
 	// If the user is using non-transactional async-put and requested non-zero send check
 	// count then this is the point at which we carry out the check for errors.
@@ -268,6 +585,63 @@ func populateAsyncPutError(sts *ibmmq.MQSTS) jms20subset.JMSException {
 
 }
 
+// applyDeliveryOptions sets the persistence, expiry and priority of a message that is
+// being sent to the specified destination, using the settings of this producer unless
+// they are overridden by the destination.
+func (producer ProducerImpl) applyDeliveryOptions(dest jms20subset.Destination, putmqmd *ibmmq.MQMD) {
+
+	// The destination can override the settings of the producer, for example
+	// if it was created from a queue URI.
+	persistence := producer.deliveryMode
+	timeToLive := producer.timeToLive
+	priority := producer.priority
+
+	if queue, ok := dest.(jms20subset.Queue); ok {
+
+		if queue.GetPersistence() != jms20subset.Destination_PERSISTENCE_APP {
+			persistence = queue.GetPersistence()
+		}
+
+		if queue.GetExpiry() != jms20subset.Destination_EXPIRY_APP {
+			timeToLive = queue.GetExpiry()
+			if timeToLive == jms20subset.Destination_EXPIRY_UNLIMITED {
+				putmqmd.Expiry = ibmmq.MQEI_UNLIMITED
+			}
+		}
+
+		if queue.GetPriority() != jms20subset.Destination_PRIORITY_APP {
+			priority = queue.GetPriority()
+		}
+	}
+
+	// Convert the JMS persistence into the equivalent MQ message descriptor
+	// attribute, or let the queue manager apply the default from the queue.
+	switch persistence {
+	case jms20subset.Destination_PERSISTENCE_QDEF:
+		putmqmd.Persistence = ibmmq.MQPER_PERSISTENCE_AS_Q_DEF
+	case jms20subset.DeliveryMode_NON_PERSISTENT, jms20subset.Destination_PERSISTENCE_HIGH:
+		putmqmd.Persistence = ibmmq.MQPER_NOT_PERSISTENT
+	default:
+		putmqmd.Persistence = ibmmq.MQPER_PERSISTENT
+	}
+
+	// If the producer has a TTL specified then apply it to the put MQMD so
+	// that MQ will honour it.
+	if timeToLive > 0 {
+		// Note that JMS timeToLive in milliseconds, whereas MQMD Expiry expects
+		// 10ths of a second
+		putmqmd.Expiry = (int32(timeToLive) / 100)
+	}
+
+	// Convert the JMS priority into the equivalent MQ message descriptor
+	// attribute, or let the queue manager apply the default from the queue.
+	if priority == jms20subset.Destination_PRIORITY_QDEF {
+		putmqmd.Priority = ibmmq.MQPRI_PRIORITY_AS_Q_DEF
+	} else {
+		putmqmd.Priority = int32(priority)
+	}
+}
+
 // SetDeliveryMode contains the MQ logic necessary to store the specified
 // delivery mode parameter inside the Producer object so that it can be
 // applied when sending messages using this Producer.
@@ -345,3 +719,29 @@ func (producer *ProducerImpl) SetPriority(priority int) jms20subset.JMSProducer
 func (producer *ProducerImpl) GetPriority() int {
 	return producer.priority
 }
+
+// SetCompression stores the algorithm that is used to compress the body of
+// messages sent using this Producer.
+func (producer *ProducerImpl) SetCompression(algorithm string) jms20subset.JMSProducer {
+
+	// Check that the specified algorithm is one of the values that we permit,
+	// and if so store that value inside producer.
+	if algorithm == jms20subset.Compression_NONE || algorithm == jms20subset.Compression_GZIP {
+		producer.compression = algorithm
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid Compression specified: " + algorithm)
+	}
+
+	return producer
+}
+
+// GetCompression returns the algorithm that is used to compress the body of
+// messages sent by this producer.
+func (producer *ProducerImpl) GetCompression() string {
+	return producer.compression
+}
@@ -0,0 +1,894 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// ProtectionPolicy_QOP_NONE indicates that messages are sent and received without
+// any protection. This is the default.
+const ProtectionPolicy_QOP_NONE string = ""
+
+// ProtectionPolicy_QOP_INTEGRITY indicates that messages are signed by the sender, so
+// that the receiver can detect whether they have been altered.
+const ProtectionPolicy_QOP_INTEGRITY string = "integrity"
+
+// ProtectionPolicy_QOP_PRIVACY indicates that messages are signed by the sender and
+// then encrypted so that only the intended recipients can read them.
+const ProtectionPolicy_QOP_PRIVACY string = "privacy"
+
+// ProtectionPolicy_QOP_CONFIDENTIALITY indicates that messages are encrypted so that
+// only the intended recipients can read them, but are not signed.
+const ProtectionPolicy_QOP_CONFIDENTIALITY string = "confidentiality"
+
+// MessageImpl_PROPERTY_PROTECTION is the name of the message property that marks
+// a message whose body and properties have been protected, and records the quality
+// of protection that was applied.
+const MessageImpl_PROPERTY_PROTECTION string = "mqjms_Protection"
+
+// ProtectionPolicy describes how messages sent to or received from a destination
+// are protected, in a similar way to the security policies of IBM MQ Advanced
+// Message Security. Policies are configured on the ConnectionFactory, keyed by the
+// name of the destination.
+//
+// When a policy applies, the body, format and properties of the message are
+// protected together, so that none of them are visible on the queue.
+type ProtectionPolicy struct {
+	QualityOfProtection string // Default to ProtectionPolicy_QOP_NONE
+
+	// Used by the sender to sign messages (INTEGRITY and PRIVACY). The key may
+	// be either RSA or ECDSA.
+	SigningCertificate *x509.Certificate
+	SigningKey         crypto.Signer
+
+	// Used by the sender to encrypt messages (PRIVACY and CONFIDENTIALITY) so
+	// that they can be read by the owner of any one of these RSA certificates.
+	RecipientCertificates []*x509.Certificate
+
+	// Used by the receiver to decrypt messages that were encrypted for this
+	// certificate.
+	DecryptionCertificate *x509.Certificate
+	DecryptionKey         *rsa.PrivateKey
+
+	// Used by the receiver to decide which senders are trusted. A signature is only
+	// accepted if the certificate carried in the message is one of the TrustedSigners,
+	// or is issued by one of the TrustedRoots (which can include intermediate CAs), and
+	// is within its validity period. At least one of the two must be set to receive
+	// signed messages.
+	TrustedSigners []*x509.Certificate
+	TrustedRoots   *x509.CertPool
+
+	// Used by the receiver to hold messages that fail verification or decryption,
+	// which are moved there in the same unit of work as they are received rather
+	// than being lost. Defaults to ProtectionPolicy_DEFAULT_ERROR_QUEUE.
+	ErrorQueue string
+}
+
+// ProtectionPolicy_DEFAULT_ERROR_QUEUE is the queue that messages which fail
+// verification or decryption are moved to if the policy doesn't name one, which
+// is the same queue as is used by IBM MQ Advanced Message Security.
+const ProtectionPolicy_DEFAULT_ERROR_QUEUE string = "SYSTEM.PROTECTION.ERROR.QUEUE"
+
+// protectedContent holds the parts of the message that are hidden from view
+// when the message is protected.
+type protectedContent struct {
+	Format     string
+	Body       []byte
+	Properties map[string]interface{}
+}
+
+// signedContent holds the encoded protectedContent, together with the signature
+// and certificate of the sender if the message was signed.
+type signedContent struct {
+	Content           []byte
+	SignerCertificate []byte
+	Signature         []byte
+}
+
+// recipientKey holds the message encryption key, encrypted with the public key
+// of one recipient and identified by the fingerprint of their certificate.
+type recipientKey struct {
+	Fingerprint []byte
+	Key         []byte
+}
+
+// protectedEnvelope is the body of a protected message as it is sent to the queue.
+type protectedEnvelope struct {
+	QualityOfProtection string
+	Payload             []byte
+	Nonce               []byte
+	Recipients          []recipientKey
+}
+
+// protectedEnvelopeHeader identifies the body of a protected message, and the
+// version of its layout.
+//
+// Each part of the envelope is encoded as a fixed layout of big-endian lengths and
+// values rather than with a general purpose encoding, so that the unauthenticated
+// data that is read from the queue can be parsed with nothing more than bounds checks.
+var protectedEnvelopeHeader = []byte("MQJP\x01")
+
+// Type codes of the property values in an encoded protectedContent.
+const (
+	protectedPropertyNull byte = iota
+	protectedPropertyString
+	protectedPropertyBytes
+	protectedPropertyBool
+	protectedPropertyInt8
+	protectedPropertyInt16
+	protectedPropertyInt32
+	protectedPropertyInt64
+	protectedPropertyFloat32
+	protectedPropertyFloat64
+)
+
+// protectionEncoder builds the fixed binary layout of a part of the envelope.
+type protectionEncoder struct {
+	buf []byte
+}
+
+// writeUint32 appends a big-endian 32 bit value.
+func (enc *protectionEncoder) writeUint32(value uint32) {
+	enc.buf = binary.BigEndian.AppendUint32(enc.buf, value)
+}
+
+// writeBytes appends a value preceded by its length.
+func (enc *protectionEncoder) writeBytes(value []byte) {
+	enc.writeUint32(uint32(len(value)))
+	enc.buf = append(enc.buf, value...)
+}
+
+// protectionDecoder reads the fixed binary layout of a part of the envelope,
+// checking every length against the data that remains. Once an error has
+// occurred each read returns a zero value, so that it need only be checked once
+// at the end.
+type protectionDecoder struct {
+	data []byte
+	err  error
+}
+
+// readUint32 reads a big-endian 32 bit value.
+func (dec *protectionDecoder) readUint32() uint32 {
+
+	if dec.err != nil {
+		return 0
+	}
+
+	if len(dec.data) < 4 {
+		dec.err = errors.New("Protected message is truncated")
+		return 0
+	}
+
+	value := binary.BigEndian.Uint32(dec.data)
+	dec.data = dec.data[4:]
+	return value
+}
+
+// readBytes reads a value that is preceded by its length.
+func (dec *protectionDecoder) readBytes() []byte {
+
+	length := dec.readUint32()
+	if dec.err != nil {
+		return nil
+	}
+
+	if uint64(length) > uint64(len(dec.data)) {
+		dec.err = errors.New("Protected message is truncated")
+		return nil
+	}
+
+	value := dec.data[:length]
+	dec.data = dec.data[length:]
+	return value
+}
+
+// readCount reads the number of entries in a list, each of which is at least
+// minEntryLength bytes, so that the count can't exceed the data that remains.
+func (dec *protectionDecoder) readCount(minEntryLength int) int {
+
+	count := dec.readUint32()
+	if dec.err == nil && uint64(count)*uint64(minEntryLength) > uint64(len(dec.data)) {
+		dec.err = errors.New("Protected message is truncated")
+		return 0
+	}
+
+	return int(count)
+}
+
+// finish returns the first error that occurred, or an error if there is data
+// left over that should not be there.
+func (dec *protectionDecoder) finish() error {
+
+	if dec.err == nil && len(dec.data) > 0 {
+		dec.err = errors.New("Protected message has unexpected trailing data")
+	}
+
+	return dec.err
+}
+
+// encodeContent encodes the parts of the message that are hidden when it is protected.
+func encodeContent(content protectedContent) ([]byte, error) {
+
+	enc := &protectionEncoder{}
+	enc.writeBytes([]byte(content.Format))
+	enc.writeBytes(content.Body)
+	enc.writeUint32(uint32(len(content.Properties)))
+
+	for name, value := range content.Properties {
+
+		var valueType byte
+		var valueBytes []byte
+
+		switch typedValue := value.(type) {
+		case nil:
+			valueType = protectedPropertyNull
+		case string:
+			valueType, valueBytes = protectedPropertyString, []byte(typedValue)
+		case []byte:
+			valueType, valueBytes = protectedPropertyBytes, typedValue
+		case bool:
+			valueType, valueBytes = protectedPropertyBool, []byte{0}
+			if typedValue {
+				valueBytes[0] = 1
+			}
+		case int8:
+			valueType, valueBytes = protectedPropertyInt8, binary.BigEndian.AppendUint64(nil, uint64(typedValue))
+		case int16:
+			valueType, valueBytes = protectedPropertyInt16, binary.BigEndian.AppendUint64(nil, uint64(typedValue))
+		case int32:
+			valueType, valueBytes = protectedPropertyInt32, binary.BigEndian.AppendUint64(nil, uint64(typedValue))
+		case int64:
+			valueType, valueBytes = protectedPropertyInt64, binary.BigEndian.AppendUint64(nil, uint64(typedValue))
+		case float32:
+			valueType, valueBytes = protectedPropertyFloat32, binary.BigEndian.AppendUint64(nil, math.Float64bits(float64(typedValue)))
+		case float64:
+			valueType, valueBytes = protectedPropertyFloat64, binary.BigEndian.AppendUint64(nil, math.Float64bits(typedValue))
+		default:
+			return nil, errors.New("Unsupported type for property " + name)
+		}
+
+		enc.writeBytes([]byte(name))
+		enc.writeBytes([]byte{valueType})
+		enc.writeBytes(valueBytes)
+	}
+
+	return enc.buf, nil
+}
+
+// decodeContent reverses encodeContent.
+func decodeContent(data []byte) (protectedContent, error) {
+
+	dec := &protectionDecoder{data: data}
+
+	content := protectedContent{
+		Format:     string(dec.readBytes()),
+		Body:       dec.readBytes(),
+		Properties: map[string]interface{}{},
+	}
+
+	// Each property is at least three lengths.
+	count := dec.readCount(12)
+	for i := 0; i < count && dec.err == nil; i++ {
+
+		name := string(dec.readBytes())
+		valueType := dec.readBytes()
+		valueBytes := dec.readBytes()
+		if dec.err != nil {
+			break
+		}
+
+		if len(valueType) != 1 {
+			return content, errors.New("Invalid type for property " + name)
+		}
+
+		// Numeric values are all encoded in eight bytes.
+		var number uint64
+		if valueType[0] >= protectedPropertyInt8 && valueType[0] <= protectedPropertyFloat64 {
+			if len(valueBytes) != 8 {
+				return content, errors.New("Invalid value for property " + name)
+			}
+			number = binary.BigEndian.Uint64(valueBytes)
+		}
+
+		var value interface{}
+
+		switch valueType[0] {
+		case protectedPropertyNull:
+			value = nil
+		case protectedPropertyString:
+			value = string(valueBytes)
+		case protectedPropertyBytes:
+			value = append([]byte{}, valueBytes...)
+		case protectedPropertyBool:
+			if len(valueBytes) != 1 {
+				return content, errors.New("Invalid value for property " + name)
+			}
+			value = valueBytes[0] != 0
+		case protectedPropertyInt8:
+			value = int8(number)
+		case protectedPropertyInt16:
+			value = int16(number)
+		case protectedPropertyInt32:
+			value = int32(number)
+		case protectedPropertyInt64:
+			value = int64(number)
+		case protectedPropertyFloat32:
+			value = float32(math.Float64frombits(number))
+		case protectedPropertyFloat64:
+			value = math.Float64frombits(number)
+		default:
+			return content, errors.New("Invalid type for property " + name)
+		}
+
+		content.Properties[name] = value
+	}
+
+	return content, dec.finish()
+}
+
+// encodeSigned encodes the content along with its signature.
+func encodeSigned(signed signedContent) []byte {
+
+	enc := &protectionEncoder{}
+	enc.writeBytes(signed.Content)
+	enc.writeBytes(signed.SignerCertificate)
+	enc.writeBytes(signed.Signature)
+
+	return enc.buf
+}
+
+// decodeSigned reverses encodeSigned.
+func decodeSigned(data []byte) (signedContent, error) {
+
+	dec := &protectionDecoder{data: data}
+
+	signed := signedContent{
+		Content:           dec.readBytes(),
+		SignerCertificate: dec.readBytes(),
+		Signature:         dec.readBytes(),
+	}
+
+	return signed, dec.finish()
+}
+
+// encodeEnvelope encodes the envelope as the body of the protected message.
+func encodeEnvelope(envelope protectedEnvelope) []byte {
+
+	enc := &protectionEncoder{buf: append([]byte{}, protectedEnvelopeHeader...)}
+	enc.writeBytes([]byte(envelope.QualityOfProtection))
+	enc.writeBytes(envelope.Payload)
+	enc.writeBytes(envelope.Nonce)
+	enc.writeUint32(uint32(len(envelope.Recipients)))
+
+	for _, recipient := range envelope.Recipients {
+		enc.writeBytes(recipient.Fingerprint)
+		enc.writeBytes(recipient.Key)
+	}
+
+	return enc.buf
+}
+
+// decodeEnvelope reverses encodeEnvelope.
+func decodeEnvelope(data []byte) (protectedEnvelope, error) {
+
+	if !bytes.HasPrefix(data, protectedEnvelopeHeader) {
+		return protectedEnvelope{}, errors.New("Message body is not a protected message")
+	}
+
+	dec := &protectionDecoder{data: data[len(protectedEnvelopeHeader):]}
+
+	envelope := protectedEnvelope{
+		QualityOfProtection: string(dec.readBytes()),
+		Payload:             dec.readBytes(),
+		Nonce:               dec.readBytes(),
+	}
+
+	// Each recipient is at least two lengths.
+	count := dec.readCount(8)
+	for i := 0; i < count && dec.err == nil; i++ {
+		envelope.Recipients = append(envelope.Recipients, recipientKey{
+			Fingerprint: dec.readBytes(),
+			Key:         dec.readBytes(),
+		})
+	}
+
+	return envelope, dec.finish()
+}
+
+// LoadCertificateFromPEMFile reads an X.509 certificate from a PEM encoded file,
+// for use in a ProtectionPolicy.
+func LoadCertificateFromPEMFile(fileName string) (*x509.Certificate, error) {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("No PEM encoded certificate found in " + fileName)
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// LoadPrivateKeyFromPEMFile reads an RSA or ECDSA private key from a PEM encoded
+// file in PKCS #1, PKCS #8 or SEC 1 form, for use in a ProtectionPolicy.
+func LoadPrivateKeyFromPEMFile(fileName string) (crypto.Signer, error) {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM encoded private key found in " + fileName)
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+
+	return nil, errors.New("Unsupported private key type in " + fileName + ": " + block.Type)
+}
+
+// isSigned returns whether the quality of protection includes a signature.
+func isSigned(qop string) bool {
+	return qop == ProtectionPolicy_QOP_INTEGRITY || qop == ProtectionPolicy_QOP_PRIVACY
+}
+
+// isEncrypted returns whether the quality of protection includes encryption.
+func isEncrypted(qop string) bool {
+	return qop == ProtectionPolicy_QOP_PRIVACY || qop == ProtectionPolicy_QOP_CONFIDENTIALITY
+}
+
+// certificateFingerprint identifies a certificate by the SHA-256 hash of its encoding.
+func certificateFingerprint(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+// protectMessage signs and/or encrypts the body, format and properties of a message
+// according to the policy. The properties are removed from the message and replaced
+// with a marker so that the consumer can reverse the protection, and the properties
+// that were removed are returned so that the caller can restore them with
+// unmarkProtected once the message has been sent.
+//
+// If an error is returned then the message is left unchanged.
+//
+// The caller must hold the context lock.
+func protectMessage(policy ProtectionPolicy, msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, body []byte) ([]byte, map[string]interface{}, error) {
+
+	qop := policy.QualityOfProtection
+	if qop != ProtectionPolicy_QOP_INTEGRITY && qop != ProtectionPolicy_QOP_PRIVACY &&
+		qop != ProtectionPolicy_QOP_CONFIDENTIALITY {
+		return nil, nil, errors.New("Unsupported quality of protection: " + qop)
+	}
+
+	properties, err := readAllProperties(msgHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentBytes, err := encodeContent(protectedContent{
+		Format:     mqmd.Format,
+		Body:       body,
+		Properties: properties,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signed := signedContent{Content: contentBytes}
+
+	if isSigned(qop) {
+
+		if policy.SigningKey == nil || policy.SigningCertificate == nil {
+			return nil, nil, errors.New("A signing key and certificate are required to sign messages")
+		}
+
+		digest := sha256.Sum256(signed.Content)
+		signed.Signature, err = policy.SigningKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return nil, nil, err
+		}
+		signed.SignerCertificate = policy.SigningCertificate.Raw
+	}
+
+	envelope := protectedEnvelope{
+		QualityOfProtection: qop,
+		Payload:             encodeSigned(signed),
+	}
+
+	if isEncrypted(qop) {
+		err = encryptEnvelope(&envelope, policy.RecipientCertificates)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Now that the protected body has been built, hide the original properties
+	// and format and mark the message as protected.
+	originalFormat := mqmd.Format
+	dmpo := ibmmq.NewMQDMPO()
+	for name := range properties {
+		msgHandle.DltMP(dmpo, name)
+	}
+
+	smpo := ibmmq.NewMQSMPO()
+	pd := ibmmq.NewMQPD()
+	err = msgHandle.SetMP(smpo, MessageImpl_PROPERTY_PROTECTION, pd, qop)
+	mqmd.Format = ibmmq.MQFMT_NONE
+
+	if err != nil {
+		unmarkProtected(msgHandle, mqmd, properties, originalFormat)
+		return nil, nil, err
+	}
+
+	return encodeEnvelope(envelope), properties, nil
+}
+
+// encryptEnvelope encrypts the payload of the envelope with a new AES-256-GCM key,
+// and then encrypts that key for each of the recipients using RSA-OAEP.
+func encryptEnvelope(envelope *protectedEnvelope, recipients []*x509.Certificate) error {
+
+	if len(recipients) == 0 {
+		return errors.New("At least one recipient certificate is required to encrypt messages")
+	}
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range recipients {
+
+		publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("Recipient certificate does not contain an RSA public key: " + cert.Subject.String())
+		}
+
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, nil)
+		if err != nil {
+			return err
+		}
+
+		envelope.Recipients = append(envelope.Recipients, recipientKey{
+			Fingerprint: certificateFingerprint(cert),
+			Key:         wrapped,
+		})
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	envelope.Nonce = make([]byte, gcm.NonceSize())
+	_, err = rand.Read(envelope.Nonce)
+	if err != nil {
+		return err
+	}
+
+	envelope.Payload = gcm.Seal(nil, envelope.Nonce, envelope.Payload, []byte(envelope.QualityOfProtection))
+
+	return nil
+}
+
+// decryptEnvelope reverses encryptEnvelope using the key of the receiver.
+func decryptEnvelope(envelope *protectedEnvelope, cert *x509.Certificate, privateKey *rsa.PrivateKey) error {
+
+	if cert == nil || privateKey == nil {
+		return errors.New("A decryption key and certificate are required to decrypt messages")
+	}
+
+	fingerprint := certificateFingerprint(cert)
+
+	for _, recipient := range envelope.Recipients {
+
+		if !bytes.Equal(recipient.Fingerprint, fingerprint) {
+			continue
+		}
+
+		key, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, recipient.Key, nil)
+		if err != nil {
+			return err
+		}
+
+		gcm, err := newGCM(key)
+		if err != nil {
+			return err
+		}
+
+		envelope.Payload, err = gcm.Open(nil, envelope.Nonce, envelope.Payload, []byte(envelope.QualityOfProtection))
+		return err
+	}
+
+	return errors.New("Message was not encrypted for certificate: " + cert.Subject.String())
+}
+
+// newGCM creates an AES-GCM cipher from the specified key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// verifySignature checks that the content was signed by the certificate it carries,
+// and that the certificate is trusted by the policy.
+func verifySignature(signed signedContent, policy *ProtectionPolicy) error {
+
+	if len(signed.Signature) == 0 || len(signed.SignerCertificate) == 0 {
+		return errors.New("Message is not signed")
+	}
+
+	cert, err := x509.ParseCertificate(signed.SignerCertificate)
+	if err != nil {
+		return err
+	}
+
+	err = verifySigner(cert, policy)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(signed.Content)
+
+	switch publicKey := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signed.Signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest[:], signed.Signature) {
+			return errors.New("ECDSA signature verification failed")
+		}
+		return nil
+	default:
+		return errors.New("Unsupported signer public key type")
+	}
+}
+
+// verifySigner checks that the certificate of the sender is either one of the trusted
+// signers or is issued by one of the trusted roots, and that it is currently valid.
+func verifySigner(cert *x509.Certificate, policy *ProtectionPolicy) error {
+
+	if len(policy.TrustedSigners) == 0 && policy.TrustedRoots == nil {
+		return errors.New("A trusted signer or root certificate is required to verify messages")
+	}
+
+	now := time.Now()
+
+	for _, signer := range policy.TrustedSigners {
+		if bytes.Equal(signer.Raw, cert.Raw) {
+
+			if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+				return errors.New("Message was signed by a certificate that is not currently valid: " + cert.Subject.String())
+			}
+			return nil
+		}
+	}
+
+	if policy.TrustedRoots != nil {
+
+		// The certificate is used to sign messages rather than for TLS, so don't
+		// restrict its extended key usage.
+		_, err := cert.Verify(x509.VerifyOptions{
+			Roots:       policy.TrustedRoots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err == nil {
+			return nil
+		}
+
+		return errors.New("Message was signed by an untrusted certificate: " + cert.Subject.String() + ": " + err.Error())
+	}
+
+	return errors.New("Message was signed by an untrusted certificate: " + cert.Subject.String())
+}
+
+// unmarkProtected removes the protection marker from the message and restores its
+// original format and properties. The caller must hold the context lock.
+func unmarkProtected(msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, properties map[string]interface{}, originalFormat string) {
+
+	dmpo := ibmmq.NewMQDMPO()
+	msgHandle.DltMP(dmpo, MessageImpl_PROPERTY_PROTECTION)
+
+	smpo := ibmmq.NewMQSMPO()
+	pd := ibmmq.NewMQPD()
+	for name, value := range properties {
+		msgHandle.SetMP(smpo, name, pd, value)
+	}
+
+	mqmd.Format = originalFormat
+}
+
+// unprotectMessage checks whether a message that has been received is marked as
+// protected, and if so verifies and/or decrypts it according to the policy for the
+// destination, then restores the original format and properties of the message and
+// returns the original body.
+//
+// If a policy applies to the destination then messages that are not protected are
+// rejected. If no policy applies then protected messages are returned unchanged.
+//
+// The caller must hold the context lock.
+func unprotectMessage(policy *ProtectionPolicy, msgHandle *ibmmq.MQMessageHandle, mqmd *ibmmq.MQMD, body []byte) ([]byte, error) {
+
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+
+	_, value, err := msgHandle.InqMP(impo, pd, MessageImpl_PROPERTY_PROTECTION)
+	if err != nil {
+
+		// The marker is not present, so the message is not protected.
+		if policy != nil && policy.QualityOfProtection != ProtectionPolicy_QOP_NONE {
+			return nil, errors.New("Message is not protected but the policy requires " + policy.QualityOfProtection)
+		}
+
+		return body, nil
+	}
+
+	if policy == nil {
+		return body, nil
+	}
+
+	envelope, err := decodeEnvelope(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the message has at least the protection that the policy requires,
+	// and that the envelope agrees with the marker.
+	qop, _ := value.(string)
+	if qop != envelope.QualityOfProtection {
+		return nil, errors.New("Message protection marker does not match its content")
+	}
+	if (isSigned(policy.QualityOfProtection) && !isSigned(qop)) ||
+		(isEncrypted(policy.QualityOfProtection) && !isEncrypted(qop)) {
+		return nil, errors.New("Message protected with " + qop + " but the policy requires " + policy.QualityOfProtection)
+	}
+
+	if isEncrypted(qop) {
+		err = decryptEnvelope(&envelope, policy.DecryptionCertificate, policy.DecryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signed, err := decodeSigned(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// The content is only decoded once its signature has been verified. A message
+	// that carries a signature is always verified, even if the policy doesn't require it.
+	if isSigned(qop) || len(signed.Signature) > 0 {
+		err = verifySignature(signed, policy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content, err := decodeContent(signed.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarkProtected(msgHandle, mqmd, content.Properties, content.Format)
+
+	return content.Body, nil
+}
+
+// checkStreamProtection returns an exception if a protection policy applies to the
+// destination, as a stream is sent and received one segment at a time and so cannot
+// be signed or encrypted as a whole. Rejecting it ensures that the content of the
+// stream is never sent or accepted without the protection that the policy requires.
+func checkStreamProtection(ctx ContextImpl, dest jms20subset.Destination) jms20subset.JMSException {
+
+	if policy, ok := ctx.protectionPolicies[dest.GetDestinationName()]; ok &&
+		policy.QualityOfProtection != ProtectionPolicy_QOP_NONE {
+
+		return jms20subset.CreateJMSException("ProtectionNotSupported", "ProtectionNotSupported",
+			errors.New("Streams cannot be used with a destination that has a protection policy of "+policy.QualityOfProtection))
+	}
+
+	return nil
+}
+
+// moveToErrorQueue puts a message that failed verification or decryption to the
+// error queue of the policy, exactly as it was received and with its original
+// context, under syncpoint so that it is moved in the same unit of work as it was
+// received. The caller must hold the context lock.
+func (consumer ConsumerImpl) moveToErrorQueue(policy *ProtectionPolicy, mqmd *ibmmq.MQMD, msgHandle *ibmmq.MQMessageHandle, buffer []byte) error {
+
+	mqod := ibmmq.NewMQOD()
+	mqod.ObjectType = ibmmq.MQOT_Q
+	mqod.ObjectName = policy.ErrorQueue
+	if mqod.ObjectName == "" {
+		mqod.ObjectName = ProtectionPolicy_DEFAULT_ERROR_QUEUE
+	}
+
+	pmo := ibmmq.NewMQPMO()
+	pmo.Options = ibmmq.MQPMO_SYNCPOINT | ibmmq.MQPMO_PASS_ALL_CONTEXT | ibmmq.MQPMO_FAIL_IF_QUIESCING
+	pmo.Context = &consumer.qObject
+	pmo.OriginalMsgHandle = *msgHandle
+
+	err := consumer.ctx.qMgr.Put1(mqod, mqmd, pmo, buffer)
+
+	// If we aren't allowed to pass the context of the message then fall back to
+	// default context, which still keeps the message ID.
+	if err != nil && err.(*ibmmq.MQReturn).MQRC == ibmmq.MQRC_NOT_AUTHORIZED {
+		pmo.Options &^= ibmmq.MQPMO_PASS_ALL_CONTEXT
+		pmo.Context = nil
+		err = consumer.ctx.qMgr.Put1(mqod, mqmd, pmo, buffer)
+	}
+
+	return err
+}
+
+// readAllProperties returns the names and values of all of the properties of a
+// message. The caller must hold the context lock.
+func readAllProperties(msgHandle *ibmmq.MQMessageHandle) (map[string]interface{}, error) {
+
+	impo := ibmmq.NewMQIMPO()
+	pd := ibmmq.NewMQPD()
+	properties := map[string]interface{}{}
+
+	impo.Options = ibmmq.MQIMPO_INQ_FIRST
+	for {
+
+		name, value, err := msgHandle.InqMP(impo, pd, "%")
+		impo.Options = ibmmq.MQIMPO_INQ_NEXT
+
+		if err != nil {
+			if err.(*ibmmq.MQReturn).MQRC == ibmmq.MQRC_PROPERTY_NOT_AVAILABLE {
+				// Read all properties
+				return properties, nil
+			}
+			return nil, err
+		}
+
+		properties[name] = value
+	}
+}
@@ -10,17 +10,177 @@
 package mqjms
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
 )
 
 // QueueImpl encapsulates the provider-specific attributes necessary to
 // communicate with an IBM MQ queue.
 type QueueImpl struct {
 	queueName       string
+	qMgrName        string
 	putAsyncAllowed int
+
+	// Destination-level overrides for the equivalent producer settings.
+	persistence int
+	priority    int
+	expiry      int
+
+	// Conversion of messages that are received from the queue.
+	receiveConversion int
+	receiveCCSID      int
+}
+
+// queueURIPrefix is the scheme used to describe a queue in the style of the IBM MQ
+// classes for JMS, for example queue://QM1/APP.REQ?persistence=2&priority=6
+const queueURIPrefix = "queue://"
+
+// newQueueImpl creates a queue object with the default attributes, which defer
+// to the settings of the producer that sends to it.
+func newQueueImpl(queueName string) QueueImpl {
+
+	return QueueImpl{
+		queueName:       queueName,
+		putAsyncAllowed: jms20subset.Destination_PUT_ASYNC_ALLOWED_AS_DEST,
+		persistence:     jms20subset.Destination_PERSISTENCE_APP,
+		priority:        jms20subset.Destination_PRIORITY_APP,
+		expiry:          jms20subset.Destination_EXPIRY_APP,
+
+		receiveConversion: jms20subset.Destination_RECEIVE_CONVERSION_CLIENT_MSG,
+		receiveCCSID:      jms20subset.Destination_RECEIVE_CCSID_DEFAULT,
+	}
+}
+
+// parseQueueURI creates a queue object from a URI of the form
+// queue://QMGR/QUEUE?name=value&name=value, where the queue manager name can be
+// omitted (queue:///QUEUE) to indicate the queue manager the application is
+// connected to.
+//
+// The options that are understood are persistence, priority, expiry (in
+// milliseconds), receiveConversion and receiveCCSID. Any other options, such as
+// those that the IBM MQ classes for JMS add to a reply-to URI, are ignored. Note
+// that this includes targetClient, so the message properties that are set by this
+// library (for example jms.Dst) are still sent, and are passed as an MQRFH2 header
+// to receivers that don't ask for properties in a message handle.
+//
+// An option with an invalid value is left at its default and reported in the
+// error, along with the queue, so that the queue can still be used. If the URI
+// doesn't contain a queue name then the queue that is returned has an empty name.
+func parseQueueURI(uri string) (QueueImpl, error) {
+
+	remainder := strings.TrimPrefix(uri, queueURIPrefix)
+
+	options := ""
+	if idx := strings.Index(remainder, "?"); idx >= 0 {
+		options = remainder[idx+1:]
+		remainder = remainder[:idx]
+	}
+
+	// The queue manager name is separated from the queue name by the first slash,
+	// which must be present even if the queue manager name is empty.
+	idx := strings.Index(remainder, "/")
+	if idx < 0 || idx == len(remainder)-1 {
+		return newQueueImpl(""), errors.New("queue name not found")
+	}
+
+	queue := newQueueImpl(remainder[idx+1:])
+
+	var errs []error
+
+	if qMgrName := remainder[:idx]; len(qMgrName) <= int(ibmmq.MQ_Q_MGR_NAME_LENGTH) {
+		queue.qMgrName = qMgrName
+	} else {
+		errs = append(errs, errors.New("queue manager name is too long"))
+	}
+
+	if options == "" {
+		return queue, errors.Join(errs...)
+	}
+
+	for _, option := range strings.Split(options, "&") {
+
+		name, valueStr, _ := strings.Cut(option, "=")
+
+		switch name {
+		case "persistence", "priority", "expiry", "receiveConversion", "receiveCCSID":
+		default:
+			// Not an option that this library understands.
+			continue
+		}
+
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			errs = append(errs, errors.New("invalid value for option "+name))
+			continue
+		}
+
+		valid := false
+
+		switch name {
+		case "persistence":
+			if valid = isValidPersistence(value); valid {
+				queue.persistence = value
+			}
+
+		case "priority":
+			if valid = isValidPriority(value); valid {
+				queue.priority = value
+			}
+
+		case "expiry":
+			if valid = isValidExpiry(value); valid {
+				queue.expiry = value
+			}
+
+		case "receiveConversion":
+			if valid = isValidReceiveConversion(value); valid {
+				queue.receiveConversion = value
+			}
+
+		case "receiveCCSID":
+			if valid = value > 0; valid {
+				queue.receiveCCSID = value
+			}
+		}
+
+		if !valid {
+			errs = append(errs, errors.New("invalid value for option "+name))
+		}
+	}
+
+	return queue, errors.Join(errs...)
+}
+
+// isValidReceiveConversion returns whether the value is a permitted receive conversion setting.
+func isValidReceiveConversion(conversion int) bool {
+	return conversion == jms20subset.Destination_RECEIVE_CONVERSION_CLIENT_MSG ||
+		conversion == jms20subset.Destination_RECEIVE_CONVERSION_QMGR
+}
+
+// isValidPersistence returns whether the value is a permitted queue persistence setting.
+func isValidPersistence(persistence int) bool {
+	return persistence == jms20subset.Destination_PERSISTENCE_APP ||
+		persistence == jms20subset.Destination_PERSISTENCE_QDEF ||
+		persistence == jms20subset.Destination_PERSISTENCE_NON ||
+		persistence == jms20subset.Destination_PERSISTENCE_PERS ||
+		persistence == jms20subset.Destination_PERSISTENCE_HIGH
+}
+
+// isValidPriority returns whether the value is a permitted queue priority setting.
+func isValidPriority(priority int) bool {
+	return priority == jms20subset.Destination_PRIORITY_APP ||
+		priority == jms20subset.Destination_PRIORITY_QDEF ||
+		(priority >= 0 && priority <= 9)
+}
+
+// isValidExpiry returns whether the value is a permitted queue expiry setting.
+func isValidExpiry(expiry int) bool {
+	return expiry == jms20subset.Destination_EXPIRY_APP || expiry >= 0
 }
 
 // GetQueueName returns the provider-specific name of the queue that is
@@ -39,6 +199,152 @@ func (queue QueueImpl) GetDestinationName() string {
 
 }
 
+// SetQueueManagerName sets the name of the queue manager on which the queue is
+// hosted, so that messages can be sent to a queue on a remote queue manager.
+func (queue QueueImpl) SetQueueManagerName(qMgrName string) jms20subset.Queue {
+
+	// Queue manager names are limited to 48 characters by MQ.
+	if len(qMgrName) <= int(ibmmq.MQ_Q_MGR_NAME_LENGTH) {
+
+		queue.qMgrName = qMgrName
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid QueueManagerName specified: " + qMgrName)
+	}
+
+	return queue
+}
+
+// GetQueueManagerName returns the name of the queue manager on which the queue
+// is hosted, or an empty string to indicate the queue manager to which the
+// application is connected.
+func (queue QueueImpl) GetQueueManagerName() string {
+	return queue.qMgrName
+}
+
+// SetPersistence sets the persistence of messages sent to this queue, overriding
+// the delivery mode of the producer.
+func (queue QueueImpl) SetPersistence(persistence int) jms20subset.Queue {
+
+	if isValidPersistence(persistence) {
+
+		queue.persistence = persistence
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid Persistence specified: " + strconv.Itoa(persistence))
+	}
+
+	return queue
+}
+
+// GetPersistence returns the persistence setting for this queue.
+func (queue QueueImpl) GetPersistence() int {
+	return queue.persistence
+}
+
+// SetPriority sets the priority of messages sent to this queue, overriding the
+// priority of the producer.
+func (queue QueueImpl) SetPriority(priority int) jms20subset.Queue {
+
+	if isValidPriority(priority) {
+
+		queue.priority = priority
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid Priority specified: " + strconv.Itoa(priority))
+	}
+
+	return queue
+}
+
+// GetPriority returns the priority setting for this queue.
+func (queue QueueImpl) GetPriority() int {
+	return queue.priority
+}
+
+// SetExpiry sets the time to live in milliseconds of messages sent to this queue,
+// overriding the time to live of the producer.
+func (queue QueueImpl) SetExpiry(expiry int) jms20subset.Queue {
+
+	if isValidExpiry(expiry) {
+
+		queue.expiry = expiry
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid Expiry specified: " + strconv.Itoa(expiry))
+	}
+
+	return queue
+}
+
+// GetExpiry returns the expiry setting for this queue.
+func (queue QueueImpl) GetExpiry() int {
+	return queue.expiry
+}
+
+// SetReceiveConversion sets whether the queue manager converts messages that are
+// received from this queue.
+func (queue QueueImpl) SetReceiveConversion(conversion int) jms20subset.Queue {
+
+	if isValidReceiveConversion(conversion) {
+
+		queue.receiveConversion = conversion
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid ReceiveConversion specified: " + strconv.Itoa(conversion))
+	}
+
+	return queue
+}
+
+// GetReceiveConversion returns the receive conversion setting for this queue.
+func (queue QueueImpl) GetReceiveConversion() int {
+	return queue.receiveConversion
+}
+
+// SetReceiveCCSID sets the CCSID that the queue manager converts messages into.
+func (queue QueueImpl) SetReceiveCCSID(ccsid int) jms20subset.Queue {
+
+	if ccsid > 0 {
+
+		queue.receiveCCSID = ccsid
+
+	} else {
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid ReceiveCCSID specified: " + strconv.Itoa(ccsid))
+	}
+
+	return queue
+}
+
+// GetReceiveCCSID returns the receive CCSID for this queue.
+func (queue QueueImpl) GetReceiveCCSID() int {
+	return queue.receiveCCSID
+}
+
 // SetPutAsyncAllowed allows the async allowed setting to be updated.
 func (queue QueueImpl) SetPutAsyncAllowed(paa int) jms20subset.Queue {
 
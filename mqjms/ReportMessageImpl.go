@@ -0,0 +1,118 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"strconv"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// ReportMessageImpl is the IBM MQ implementation of a report message, which is
+// received whenever the MsgType of the MQMD is MQMT_REPORT.
+type ReportMessageImpl struct {
+	BytesMessageImpl
+}
+
+// feedbackNames describes the feedback codes that are not also reason codes.
+var feedbackNames = map[int32]string{
+	ibmmq.MQFB_QUIT:                   "MQFB_QUIT",
+	ibmmq.MQFB_EXPIRATION:             "MQFB_EXPIRATION",
+	ibmmq.MQFB_COA:                    "MQFB_COA",
+	ibmmq.MQFB_COD:                    "MQFB_COD",
+	ibmmq.MQFB_CHANNEL_COMPLETED:      "MQFB_CHANNEL_COMPLETED",
+	ibmmq.MQFB_CHANNEL_FAIL_RETRY:     "MQFB_CHANNEL_FAIL_RETRY",
+	ibmmq.MQFB_CHANNEL_FAIL:           "MQFB_CHANNEL_FAIL",
+	ibmmq.MQFB_APPL_CANNOT_BE_STARTED: "MQFB_APPL_CANNOT_BE_STARTED",
+	ibmmq.MQFB_TM_ERROR:               "MQFB_TM_ERROR",
+	ibmmq.MQFB_APPL_TYPE_ERROR:        "MQFB_APPL_TYPE_ERROR",
+	ibmmq.MQFB_STOPPED_BY_MSG_EXIT:    "MQFB_STOPPED_BY_MSG_EXIT",
+	ibmmq.MQFB_ACTIVITY:               "MQFB_ACTIVITY",
+	ibmmq.MQFB_XMIT_Q_MSG_ERROR:       "MQFB_XMIT_Q_MSG_ERROR",
+	ibmmq.MQFB_PAN:                    "MQFB_PAN",
+	ibmmq.MQFB_NAN:                    "MQFB_NAN",
+	ibmmq.MQFB_STOPPED_BY_CHAD_EXIT:   "MQFB_STOPPED_BY_CHAD_EXIT",
+	ibmmq.MQFB_NOT_DELIVERED:          "MQFB_NOT_DELIVERED",
+	ibmmq.MQFB_NOT_FORWARDED:          "MQFB_NOT_FORWARDED",
+	ibmmq.MQFB_UNSUPPORTED_DELIVERY:   "MQFB_UNSUPPORTED_DELIVERY",
+	ibmmq.MQFB_UNSUPPORTED_FORWARDING: "MQFB_UNSUPPORTED_FORWARDING",
+	ibmmq.MQFB_DATA_LENGTH_ZERO:       "MQFB_DATA_LENGTH_ZERO",
+	ibmmq.MQFB_DATA_LENGTH_NEGATIVE:   "MQFB_DATA_LENGTH_NEGATIVE",
+	ibmmq.MQFB_DATA_LENGTH_TOO_BIG:    "MQFB_DATA_LENGTH_TOO_BIG",
+	ibmmq.MQFB_BUFFER_OVERFLOW:        "MQFB_BUFFER_OVERFLOW",
+	ibmmq.MQFB_LENGTH_OFF_BY_ONE:      "MQFB_LENGTH_OFF_BY_ONE",
+	ibmmq.MQFB_IIH_ERROR:              "MQFB_IIH_ERROR",
+	ibmmq.MQFB_NOT_AUTHORIZED_FOR_IMS: "MQFB_NOT_AUTHORIZED_FOR_IMS",
+	ibmmq.MQFB_DATA_LENGTH_TOO_SHORT:  "MQFB_DATA_LENGTH_TOO_SHORT",
+	ibmmq.MQFB_IMS_ERROR:              "MQFB_IMS_ERROR",
+	ibmmq.MQFB_CICS_INTERNAL_ERROR:    "MQFB_CICS_INTERNAL_ERROR",
+	ibmmq.MQFB_CICS_NOT_AUTHORIZED:    "MQFB_CICS_NOT_AUTHORIZED",
+	ibmmq.MQFB_CICS_BRIDGE_FAILURE:    "MQFB_CICS_BRIDGE_FAILURE",
+	ibmmq.MQFB_CICS_CORREL_ID_ERROR:   "MQFB_CICS_CORREL_ID_ERROR",
+	ibmmq.MQFB_CICS_CCSID_ERROR:       "MQFB_CICS_CCSID_ERROR",
+	ibmmq.MQFB_CICS_ENCODING_ERROR:    "MQFB_CICS_ENCODING_ERROR",
+	ibmmq.MQFB_CICS_CIH_ERROR:         "MQFB_CICS_CIH_ERROR",
+	ibmmq.MQFB_CICS_UOW_ERROR:         "MQFB_CICS_UOW_ERROR",
+	ibmmq.MQFB_CICS_COMMAREA_ERROR:    "MQFB_CICS_COMMAREA_ERROR",
+	ibmmq.MQFB_CICS_APPL_NOT_STARTED:  "MQFB_CICS_APPL_NOT_STARTED",
+	ibmmq.MQFB_CICS_APPL_ABENDED:      "MQFB_CICS_APPL_ABENDED",
+	ibmmq.MQFB_CICS_DLQ_ERROR:         "MQFB_CICS_DLQ_ERROR",
+	ibmmq.MQFB_CICS_UOW_BACKED_OUT:    "MQFB_CICS_UOW_BACKED_OUT",
+}
+
+// GetReportType returns the kind of report, based on its feedback code.
+func (msg *ReportMessageImpl) GetReportType() string {
+
+	switch int32(msg.GetFeedback()) {
+	case ibmmq.MQFB_COA:
+		return jms20subset.ReportType_COA
+	case ibmmq.MQFB_COD:
+		return jms20subset.ReportType_COD
+	case ibmmq.MQFB_EXPIRATION:
+		return jms20subset.ReportType_EXPIRATION
+	case ibmmq.MQFB_PAN:
+		return jms20subset.ReportType_PAN
+	case ibmmq.MQFB_NAN:
+		return jms20subset.ReportType_NAN
+	default:
+		return jms20subset.ReportType_EXCEPTION
+	}
+}
+
+// GetFeedback returns the feedback code from the MQMD of the report.
+func (msg *ReportMessageImpl) GetFeedback() int {
+
+	if msg.mqmd == nil {
+		return int(ibmmq.MQFB_NONE)
+	}
+
+	return int(msg.mqmd.Feedback)
+}
+
+// GetFeedbackName returns a description of the feedback code of the report.
+func (msg *ReportMessageImpl) GetFeedbackName() string {
+
+	feedback := msg.GetFeedback()
+
+	if name, ok := feedbackNames[int32(feedback)]; ok {
+		return name
+	}
+
+	// Exception reports normally carry the reason code that caused the failure.
+	if feedback >= int(ibmmq.MQFB_SYSTEM_FIRST) && feedback <= int(ibmmq.MQFB_SYSTEM_LAST) {
+		if reason := ibmmq.MQItoString("RC", feedback); reason != "" {
+			return reason
+		}
+	}
+
+	// Otherwise this is an application-defined feedback code.
+	return strconv.Itoa(feedback)
+}
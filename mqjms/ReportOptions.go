@@ -0,0 +1,139 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// ReportData_NONE requests a report message that does not contain any of the
+// data from the original message.
+const ReportData_NONE int = 0
+
+// ReportData_PARTIAL requests a report message that contains the first 100 bytes
+// of the data from the original message.
+const ReportData_PARTIAL int = 1
+
+// ReportData_FULL requests a report message that contains all of the data from
+// the original message.
+const ReportData_FULL int = 2
+
+// ReportOptions builds the set of report messages that the queue manager or the
+// receiving application should generate for a message, for example to confirm
+// arrival (COA) or delivery (COD) of the message.
+//
+// The options are applied to a message using ApplyTo, which sets the
+// JMS_IBM_MQMD_Report property, and the reports are sent to the JMSReplyTo
+// destination of the message.
+type ReportOptions struct {
+	report int32
+}
+
+// NewReportOptions creates an empty set of report options.
+func NewReportOptions() *ReportOptions {
+	return &ReportOptions{report: ibmmq.MQRO_NONE}
+}
+
+// COA requests a confirm-on-arrival report when the message is put to its
+// destination queue.
+func (options *ReportOptions) COA(data int) *ReportOptions {
+	return options.withData("COA", data, ibmmq.MQRO_COA, ibmmq.MQRO_COA_WITH_DATA, ibmmq.MQRO_COA_WITH_FULL_DATA)
+}
+
+// COD requests a confirm-on-delivery report when the message is received by
+// an application.
+func (options *ReportOptions) COD(data int) *ReportOptions {
+	return options.withData("COD", data, ibmmq.MQRO_COD, ibmmq.MQRO_COD_WITH_DATA, ibmmq.MQRO_COD_WITH_FULL_DATA)
+}
+
+// Exception requests an exception report if the message cannot be delivered,
+// for example because the destination queue is full.
+func (options *ReportOptions) Exception(data int) *ReportOptions {
+	return options.withData("Exception", data, ibmmq.MQRO_EXCEPTION, ibmmq.MQRO_EXCEPTION_WITH_DATA,
+		ibmmq.MQRO_EXCEPTION_WITH_FULL_DATA)
+}
+
+// Expiration requests an expiration report if the message is discarded because
+// its time to live has passed.
+func (options *ReportOptions) Expiration(data int) *ReportOptions {
+	return options.withData("Expiration", data, ibmmq.MQRO_EXPIRATION, ibmmq.MQRO_EXPIRATION_WITH_DATA,
+		ibmmq.MQRO_EXPIRATION_WITH_FULL_DATA)
+}
+
+// PAN requests a positive action notification from the receiving application
+// once it has successfully processed the message.
+func (options *ReportOptions) PAN() *ReportOptions {
+	options.report |= ibmmq.MQRO_PAN
+	return options
+}
+
+// NAN requests a negative action notification from the receiving application
+// if it is unable to process the message.
+func (options *ReportOptions) NAN() *ReportOptions {
+	options.report |= ibmmq.MQRO_NAN
+	return options
+}
+
+// PassMsgID requests that report messages have the same JMSMessageID as the
+// original message, instead of a new one.
+func (options *ReportOptions) PassMsgID() *ReportOptions {
+	options.report |= ibmmq.MQRO_PASS_MSG_ID
+	return options
+}
+
+// PassCorrelID requests that report messages have the same JMSCorrelationID as
+// the original message, instead of the JMSMessageID of the original message.
+func (options *ReportOptions) PassCorrelID() *ReportOptions {
+	options.report |= ibmmq.MQRO_PASS_CORREL_ID
+	return options
+}
+
+// DiscardMsg requests that the message is discarded rather than being put to
+// the dead letter queue if it cannot be delivered.
+func (options *ReportOptions) DiscardMsg() *ReportOptions {
+	options.report |= ibmmq.MQRO_DISCARD_MSG
+	return options
+}
+
+// GetValue returns the combined MQRO report options.
+func (options *ReportOptions) GetValue() int {
+	return int(options.report)
+}
+
+// ApplyTo sets the report options on the message, replacing any report options
+// that were previously set.
+func (options *ReportOptions) ApplyTo(msg jms20subset.Message) jms20subset.JMSException {
+	return msg.SetIntProperty("JMS_IBM_MQMD_Report", options.GetValue())
+}
+
+// withData merges the report option that matches the requested amount of data.
+func (options *ReportOptions) withData(name string, data int, none int32, partial int32, full int32) *ReportOptions {
+
+	switch data {
+	case ReportData_NONE:
+		options.report |= none
+	case ReportData_PARTIAL:
+		options.report |= partial
+	case ReportData_FULL:
+		options.report |= full
+	default:
+		// Normally we would throw an error here to indicate that an invalid value
+		// was specified, however we have decided that it is more useful to support
+		// method chaining, which prevents us from returning an error object.
+		// Instead we settle for printing an error message to the console.
+		fmt.Println("Invalid " + name + " report data specified: " + strconv.Itoa(data))
+	}
+
+	return options
+}
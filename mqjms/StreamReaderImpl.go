@@ -0,0 +1,159 @@
+// Copyright (c) IBM Corporation 2026.
+//
+// This program and the accompanying materials are made available under the
+// terms of the Eclipse Public License 2.0, which is available at
+// http://www.eclipse.org/legal/epl-2.0.
+//
+// SPDX-License-Identifier: EPL-2.0
+
+// Package mqjms provides the implementation of the JMS style Golang interfaces to communicate with IBM MQ.
+package mqjms
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+)
+
+// streamReaderImpl reads the body of a message that was received by
+// ConsumerImpl.ReceiveStream, getting the next segment from the queue each
+// time that the previous one has been read.
+type streamReaderImpl struct {
+	consumer ConsumerImpl
+	buffer   []byte
+	segment  []byte
+	last     bool
+	closed   bool
+}
+
+// getSegment gets the next segment of the message from the queue, in logical
+// order, and only once all of the segments of the message are available. If no
+// message is available then the segment is left as nil.
+func (reader *streamReaderImpl) getSegment(gmo *ibmmq.MQGMO, selector string) jms20subset.JMSException {
+
+	consumer := reader.consumer
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	consumer.ctx.ctxLock.Lock()
+	defer consumer.ctx.ctxLock.Unlock()
+
+	if reader.buffer == nil {
+
+		myBufferSize := 32768
+
+		if consumer.ctx.receiveBufferSize > 0 {
+			myBufferSize = consumer.ctx.receiveBufferSize
+		}
+
+		reader.buffer = make([]byte, myBufferSize)
+	}
+
+	// Segmentation requires version 2 of the MQMD to return the message flags.
+	getmqmd := ibmmq.NewMQMD()
+	getmqmd.Version = ibmmq.MQMD_VERSION_2
+
+	gmo.Options |= ibmmq.MQGMO_SYNCPOINT | ibmmq.MQGMO_FAIL_IF_QUIESCING
+	gmo.Options |= ibmmq.MQGMO_LOGICAL_ORDER | ibmmq.MQGMO_ALL_SEGMENTS_AVAILABLE
+	gmo.Options |= ibmmq.MQGMO_NO_PROPERTIES
+
+	err := applySelector(selector, getmqmd, gmo)
+	if err != nil {
+		return jms20subset.CreateJMSException("ErrorParsingSelector", "ErrorParsingSelector", err)
+	}
+
+	datalen, err := consumer.qObject.Get(getmqmd, gmo, reader.buffer)
+
+	// Segments may be larger than the receive buffer, in which case the segment is
+	// left on the queue and we can try again with a buffer of the right size.
+	if err != nil && err.(*ibmmq.MQReturn).MQRC == ibmmq.MQRC_TRUNCATED_MSG_FAILED {
+		reader.buffer = make([]byte, datalen)
+		datalen, err = consumer.qObject.Get(getmqmd, gmo, reader.buffer)
+	}
+
+	if err != nil {
+
+		mqret := err.(*ibmmq.MQReturn)
+		if mqret.MQRC == ibmmq.MQRC_NO_MSG_AVAILABLE && reader.segment == nil {
+			// This isn't a real error - it's the way that MQ indicates that there
+			// is no message available to be received.
+			return nil
+		}
+
+		rcInt := int(mqret.MQRC)
+		errCode := strconv.Itoa(rcInt)
+		reason := ibmmq.MQItoString("RC", rcInt)
+		return jms20subset.CreateJMSException(reason, errCode, err)
+	}
+
+	reader.segment = reader.buffer[:datalen]
+
+	// A message that is not segmented is treated as the last segment.
+	reader.last = getmqmd.MsgFlags&ibmmq.MQMF_LAST_SEGMENT != 0 ||
+		getmqmd.MsgFlags&ibmmq.MQMF_SEGMENT == 0
+
+	// Commit as soon as the whole message has been received, unless the
+	// application controls the transaction.
+	if reader.last && consumer.ctx.sessionMode != jms20subset.JMSContextSESSIONTRANSACTED {
+
+		err = consumer.ctx.qMgr.Cmit()
+		if err != nil {
+			rcInt := int(err.(*ibmmq.MQReturn).MQRC)
+			errCode := strconv.Itoa(rcInt)
+			reason := ibmmq.MQItoString("RC", rcInt)
+			return jms20subset.CreateJMSException(reason, errCode, err)
+		}
+	}
+
+	return nil
+}
+
+// Read implements io.Reader, returning the body of the message.
+func (reader *streamReaderImpl) Read(p []byte) (int, error) {
+
+	if reader.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	for len(reader.segment) == 0 {
+
+		if reader.last {
+			return 0, io.EOF
+		}
+
+		// The remaining segments are already available, so there is no need to wait.
+		jmsErr := reader.getSegment(ibmmq.NewMQGMO(), "")
+		if jmsErr != nil {
+			return 0, jmsErr
+		}
+	}
+
+	n := copy(p, reader.segment)
+	reader.segment = reader.segment[n:]
+
+	return n, nil
+}
+
+// Close implements io.Closer. If the last segment of the message has not been
+// received then the segments that have been received are backed out so that the
+// message can be received again, unless the application controls the transaction.
+func (reader *streamReaderImpl) Close() error {
+
+	if reader.closed {
+		return nil
+	}
+	reader.closed = true
+
+	if reader.last || reader.consumer.ctx.sessionMode == jms20subset.JMSContextSESSIONTRANSACTED {
+		return nil
+	}
+
+	// Lock the context while we are making calls to the queue manager so that it
+	// doesn't conflict with the finalizer we use to delete unused MessageHandles.
+	reader.consumer.ctx.ctxLock.Lock()
+	defer reader.consumer.ctx.ctxLock.Unlock()
+
+	return reader.consumer.ctx.qMgr.Back()
+}
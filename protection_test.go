@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test that a message sent to a destination with a privacy policy is signed
+ * and encrypted, so that its body and properties cannot be read without the
+ * policy, and are restored when it is received with the policy.
+ */
+func TestProtectionPrivacy(t *testing.T) {
+
+	senderKey, senderCert := createProtectionIdentity(t, "sender")
+	receiverKey, receiverCert := createProtectionIdentity(t, "receiver")
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager without any policies, which sees messages
+	// as they are on the queue, using defer to close it automatically at the end of the function
+	plainContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if plainContext != nil {
+		defer plainContext.Close()
+	}
+
+	// Sign and encrypt messages sent to the queue, and decrypt and verify them on receipt.
+	cf.ProtectionPolicies = map[string]mqjms.ProtectionPolicy{
+		"DEV.QUEUE.1": {
+			QualityOfProtection:   mqjms.ProtectionPolicy_QOP_PRIVACY,
+			SigningCertificate:    senderCert,
+			SigningKey:            senderKey,
+			RecipientCertificates: []*x509.Certificate{receiverCert},
+			DecryptionCertificate: receiverCert,
+			DecryptionKey:         receiverKey,
+			TrustedSigners:        []*x509.Certificate{senderCert},
+		},
+	}
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(10000)
+
+	// Send a TextMessage with a property, which is left unchanged after it has been sent.
+	msgBody := "Account 12345678 balance 1000.00"
+	msg := context.CreateTextMessageWithString(msgBody)
+	propErr := msg.SetStringProperty("customerId", &msgBody)
+	assert.Nil(t, propErr)
+	errSend := producer.Send(queue, msg)
+	assert.Nil(t, errSend)
+	assert.Equal(t, msgBody, *msg.GetText())
+	propValue, propErr := msg.GetStringProperty("customerId")
+	assert.Nil(t, propErr)
+	assert.Equal(t, msgBody, *propValue)
+
+	// Without the policy the body and properties are not visible.
+	plainConsumer, errCons := plainContext.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if plainConsumer != nil {
+		defer plainConsumer.Close()
+	}
+
+	rcvMsg, errRcv := plainConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch typedMsg := rcvMsg.(type) {
+	case jms20subset.BytesMessage:
+		assert.NotContains(t, string(*typedMsg.ReadBytes()), "12345678")
+	default:
+		assert.Fail(t, "Got something other than a bytes message")
+	}
+
+	propExists, propErr := rcvMsg.PropertyExists("customerId")
+	assert.Nil(t, propErr)
+	assert.False(t, propExists)
+
+	qop, propErr := rcvMsg.GetStringProperty(mqjms.MessageImpl_PROPERTY_PROTECTION)
+	assert.Nil(t, propErr)
+	assert.Equal(t, mqjms.ProtectionPolicy_QOP_PRIVACY, *qop)
+
+	// With the policy the original message is restored.
+	errSend = producer.Send(queue, msg)
+	assert.Nil(t, errSend)
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch typedMsg := rcvMsg.(type) {
+	case jms20subset.TextMessage:
+		assert.Equal(t, msgBody, *typedMsg.GetText())
+	default:
+		assert.Fail(t, "Got something other than a text message")
+	}
+
+	propValue, propErr = rcvMsg.GetStringProperty("customerId")
+	assert.Nil(t, propErr)
+	assert.Equal(t, msgBody, *propValue)
+
+	propExists, propErr = rcvMsg.PropertyExists(mqjms.MessageImpl_PROPERTY_PROTECTION)
+	assert.Nil(t, propErr)
+	assert.False(t, propExists)
+
+}
+
+/*
+ * Test that a consumer with a protection policy rejects messages that are not
+ * protected, or that are signed by a sender it does not trust, and moves them to
+ * the error queue of the policy.
+ */
+func TestProtectionRejected(t *testing.T) {
+
+	senderKey, senderCert := createProtectionIdentity(t, "sender")
+	_, otherCert := createProtectionIdentity(t, "other")
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	plainContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if plainContext != nil {
+		defer plainContext.Close()
+	}
+
+	cf.ProtectionPolicies = map[string]mqjms.ProtectionPolicy{
+		"DEV.QUEUE.1": {
+			QualityOfProtection: mqjms.ProtectionPolicy_QOP_INTEGRITY,
+			SigningCertificate:  senderCert,
+			SigningKey:          senderKey,
+			TrustedSigners:      []*x509.Certificate{otherCert},
+			ErrorQueue:          "DEV.QUEUE.2",
+		},
+	}
+
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// A message sent without protection is rejected.
+	errSend := plainContext.CreateProducer().SetTimeToLive(10000).SendString(queue, "unprotected")
+	assert.Nil(t, errSend)
+
+	_, errRcv := consumer.ReceiveNoWait()
+	assert.NotNil(t, errRcv)
+	assert.Equal(t, "UnprotectFailed", errRcv.GetReason())
+
+	// A message signed by a sender that is not trusted is rejected.
+	errSend = context.CreateProducer().SetTimeToLive(10000).SendString(queue, "untrusted")
+	assert.Nil(t, errSend)
+
+	_, errRcv = consumer.ReceiveNoWait()
+	assert.NotNil(t, errRcv)
+	assert.Equal(t, "UnprotectFailed", errRcv.GetReason())
+
+	// A signed message is also rejected by a policy that has no trust anchor,
+	// rather than trusting the certificate that the message carries.
+	cf.ProtectionPolicies = map[string]mqjms.ProtectionPolicy{
+		"DEV.QUEUE.1": {
+			QualityOfProtection: mqjms.ProtectionPolicy_QOP_INTEGRITY,
+			ErrorQueue:          "DEV.QUEUE.2",
+		},
+	}
+
+	noAnchorContext, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if noAnchorContext != nil {
+		defer noAnchorContext.Close()
+	}
+
+	noAnchorConsumer, errCons := noAnchorContext.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if noAnchorConsumer != nil {
+		defer noAnchorConsumer.Close()
+	}
+
+	errSend = context.CreateProducer().SetTimeToLive(10000).SendString(queue, "no anchor")
+	assert.Nil(t, errSend)
+
+	_, errRcv = noAnchorConsumer.ReceiveNoWait()
+	assert.NotNil(t, errRcv)
+	assert.Equal(t, "UnprotectFailed", errRcv.GetReason())
+
+	// All of the messages were removed from the queue.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+	// And moved to the error queue as they were sent, rather than being lost.
+	errorQueue := plainContext.CreateQueue("DEV.QUEUE.2")
+	errorConsumer, errCons := plainContext.CreateConsumer(errorQueue)
+	assert.Nil(t, errCons)
+	if errorConsumer != nil {
+		defer errorConsumer.Close()
+	}
+
+	for _, expected := range []string{"unprotected", "untrusted", "no anchor"} {
+
+		errorMsg, errRcv := errorConsumer.ReceiveNoWait()
+		assert.Nil(t, errRcv)
+		assert.NotNil(t, errorMsg)
+
+		if errorMsg != nil {
+			protection, _ := errorMsg.PropertyExists(mqjms.MessageImpl_PROPERTY_PROTECTION)
+			assert.Equal(t, expected != "unprotected", protection)
+		}
+	}
+
+	errorMsg, errRcv := errorConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, errorMsg)
+
+}
+
+// createProtectionIdentity generates an RSA key and a matching self-signed certificate.
+func createProtectionIdentity(t *testing.T, commonName string) (*rsa.PrivateKey, *x509.Certificate) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return key, cert
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test the creation of a queue from a URI in the style used by the IBM MQ
+ * classes for JMS, and that the options in the URI override the producer.
+ */
+func TestQueueURI(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A URI with a queue manager name and no options.
+	queue := context.CreateQueue("queue://" + cf.QMName + "/DEV.QUEUE.1")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, cf.QMName, queue.GetQueueManagerName())
+
+	// A URI for the connected queue manager, with options that override the producer.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?persistence=1&priority=6&expiry=60000&targetClient=1")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, "", queue.GetQueueManagerName())
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	producer := context.CreateProducer().
+		SetDeliveryMode(jms20subset.DeliveryMode_PERSISTENT).
+		SetPriority(2)
+
+	errSend := producer.SendString(queue, "QueueURIMsg")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc := consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 6, rcvMsg.GetJMSPriority())
+	expiryMillis := rcvMsg.GetJMSExpiration() - rcvMsg.GetJMSTimestamp()
+	assert.True(t, expiryMillis > 55000 && expiryMillis <= 60000)
+
+	// A URI that sets the options to defer to the producer.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?persistence=-1&priority=-1&expiry=-2")
+
+	errSend = producer.SendString(queue, "QueueURIMsg2")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc = consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	assert.Equal(t, jms20subset.DeliveryMode_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 2, rcvMsg.GetJMSPriority())
+	// The producer doesn't set a time to live, so the message doesn't expire.
+	expiryMillis = rcvMsg.GetJMSExpiration() - rcvMsg.GetJMSTimestamp()
+	assert.True(t, expiryMillis <= 0)
+
+	// A reply-to URI generated by the IBM MQ classes for JMS, where persistence=3
+	// (NPHIGH) is non-persistent.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?expiry=0&persistence=3&priority=4&targetClient=0")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, jms20subset.Destination_PERSISTENCE_HIGH, queue.GetPersistence())
+	assert.Equal(t, jms20subset.Destination_EXPIRY_UNLIMITED, queue.GetExpiry())
+
+	errSend = producer.SendString(queue, "QueueURIJavaMsg")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc = consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+
+	assert.Equal(t, jms20subset.DeliveryMode_NON_PERSISTENT, rcvMsg.GetJMSDeliveryMode())
+	assert.Equal(t, 4, rcvMsg.GetJMSPriority())
+
+	// persistence=0 uses the default persistence of the queue.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?persistence=0")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, jms20subset.Destination_PERSISTENCE_QDEF, queue.GetPersistence())
+
+	// Options that aren't understood are ignored, including those with values that
+	// aren't numbers, so that reply-to URIs from other applications can be used.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?targetClient=1&readAheadClosePolicy=2&brokerVersion=-1&multicast=-1&replyTo=queue:///OTHER&priority=7")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, 7, queue.GetPriority())
+
+	// An option with an invalid value is left at its default, so the queue can
+	// still be used with the settings of the producer.
+	queue = context.CreateQueue("queue:///DEV.QUEUE.1?priority=12")
+	assert.Equal(t, "DEV.QUEUE.1", queue.GetQueueName())
+	assert.Equal(t, jms20subset.Destination_PRIORITY_APP, queue.GetPriority())
+
+	errSend = producer.SendString(queue, "QueueURIMsg3")
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRvc = consumer.ReceiveNoWait()
+	assert.Nil(t, errRvc)
+	assert.NotNil(t, rcvMsg)
+	assert.Equal(t, 2, rcvMsg.GetJMSPriority())
+
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	ibmmq "github.com/ibm-messaging/mq-golang/v5/ibmmq"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test requesting COA and COD reports using ReportOptions, and receiving them
+ * as ReportMessages.
+ */
+func TestReportOptionsCOACOD(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// Set up objects for send/receive
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	replyQueue := context.CreateQueue("DEV.QUEUE.2")
+	replyConsumer, errCons := context.CreateConsumer(replyQueue)
+	assert.Nil(t, errCons)
+	if replyConsumer != nil {
+		defer replyConsumer.Close()
+	}
+
+	// Request a COA with the first 100 bytes of data, and a COD with no data,
+	// both of which carry the correlation ID of the original message.
+	options := mqjms.NewReportOptions().COA(mqjms.ReportData_PARTIAL).COD(mqjms.ReportData_NONE).PassCorrelID()
+	assert.Equal(t, int(ibmmq.MQRO_COA_WITH_DATA|ibmmq.MQRO_COD|ibmmq.MQRO_PASS_CORREL_ID), options.GetValue())
+
+	msgBody := strings.Repeat("payment ", 50)
+	sendMsg := context.CreateTextMessageWithString(msgBody)
+	sendMsg.SetJMSCorrelationID("payment-12345")
+	sendMsg.SetJMSReplyTo(replyQueue)
+	errApply := options.ApplyTo(sendMsg)
+	assert.Nil(t, errApply)
+
+	gotPropValue, propErr := sendMsg.GetIntProperty("JMS_IBM_Report_COA")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int(ibmmq.MQRO_COA_WITH_DATA), gotPropValue)
+	gotPropValue, propErr = sendMsg.GetIntProperty("JMS_IBM_Report_Pass_Correl_ID")
+	assert.Nil(t, propErr)
+	assert.Equal(t, int(ibmmq.MQRO_PASS_CORREL_ID), gotPropValue)
+	gotPropValue, propErr = sendMsg.GetIntProperty("JMS_IBM_Report_Pass_Msg_ID")
+	assert.Nil(t, propErr)
+	assert.Equal(t, 0, gotPropValue)
+
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, sendMsg)
+	assert.Nil(t, errSend)
+
+	// The COA report is returned as a ReportMessage containing the first 100 bytes.
+	rcvMsg, errRcv := replyConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch report := rcvMsg.(type) {
+	case jms20subset.ReportMessage:
+		assert.Equal(t, jms20subset.ReportType_COA, report.GetReportType())
+		assert.Equal(t, int(ibmmq.MQFB_COA), report.GetFeedback())
+		assert.Equal(t, "MQFB_COA", report.GetFeedbackName())
+		assert.Equal(t, msgBody[:100], string(*report.ReadBytes()))
+		assert.Equal(t, "payment-12345", report.GetJMSCorrelationID())
+	default:
+		assert.Fail(t, "Got something other than a report message")
+	}
+
+	// Consuming the original message generates the COD report, which has no data.
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	rcvMsg, errRcv = replyConsumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch report := rcvMsg.(type) {
+	case jms20subset.ReportMessage:
+		assert.Equal(t, jms20subset.ReportType_COD, report.GetReportType())
+		assert.Equal(t, "MQFB_COD", report.GetFeedbackName())
+		assert.Equal(t, 0, report.GetBodyLength())
+		assert.Equal(t, "payment-12345", report.GetJMSCorrelationID())
+	default:
+		assert.Fail(t, "Got something other than a report message")
+	}
+
+}
+
+/*
+ * Test that a report generated by an application, such as a positive action
+ * notification, is received as a ReportMessage.
+ */
+func TestReportMessagePAN(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Build the report in the way that a receiving application would.
+	panMsg := context.CreateBytesMessage()
+	panMsg.SetIntProperty("JMS_IBM_MsgType", int(ibmmq.MQMT_REPORT))
+	panMsg.SetIntProperty("JMS_IBM_Feedback", int(ibmmq.MQFB_PAN))
+
+	errSend := context.CreateProducer().SetTimeToLive(20000).Send(queue, panMsg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch report := rcvMsg.(type) {
+	case jms20subset.ReportMessage:
+		assert.Equal(t, jms20subset.ReportType_PAN, report.GetReportType())
+		assert.Equal(t, "MQFB_PAN", report.GetFeedbackName())
+	default:
+		assert.Fail(t, "Got something other than a report message")
+	}
+
+	// An exception report carries the reason code as its feedback.
+	excMsg := context.CreateBytesMessage()
+	excMsg.SetIntProperty("JMS_IBM_MsgType", int(ibmmq.MQMT_REPORT))
+	excMsg.SetIntProperty("JMS_IBM_Feedback", int(ibmmq.MQRC_Q_FULL))
+
+	errSend = context.CreateProducer().SetTimeToLive(20000).Send(queue, excMsg)
+	assert.Nil(t, errSend)
+
+	rcvMsg, errRcv = consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, rcvMsg)
+
+	switch report := rcvMsg.(type) {
+	case jms20subset.ReportMessage:
+		assert.Equal(t, jms20subset.ReportType_EXCEPTION, report.GetReportType())
+		assert.Equal(t, "MQRC_Q_FULL", report.GetFeedbackName())
+	default:
+		assert.Fail(t, "Got something other than a report message")
+	}
+
+}
@@ -140,3 +140,59 @@ func replyToMessage(t *testing.T, cf jms20subset.ConnectionFactory, requestQueue
 	assert.Nil(t, err2)
 
 }
+
+/*
+ * Test that the queue manager name of the reply destination is transmitted with
+ * the message, so that replies can be routed to a remote queue manager.
+ */
+func TestReplyToQueueManager(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	requestQueue := context.CreateQueue("DEV.QUEUE.1")
+	assert.Equal(t, "", requestQueue.GetQueueManagerName())
+
+	// Set up a reply queue that is hosted on a different queue manager.
+	replyQueue := context.CreateQueue("DEV.QUEUE.2").SetQueueManagerName("REMOTE.QM")
+	assert.Equal(t, "DEV.QUEUE.2", replyQueue.GetQueueName())
+	assert.Equal(t, "REMOTE.QM", replyQueue.GetQueueManagerName())
+
+	sentMsg := context.CreateTextMessageWithString("RemoteReplyMsg")
+	sentMsg.SetJMSReplyTo(replyQueue)
+	assert.Equal(t, "REMOTE.QM", sentMsg.GetJMSReplyTo().(jms20subset.Queue).GetQueueManagerName())
+
+	// Explicitly naming the queue manager we are connected to has the same effect as
+	// leaving it blank.
+	sendQueue := context.CreateQueue("DEV.QUEUE.1").SetQueueManagerName(cf.QMName)
+	err := context.CreateProducer().SetTimeToLive(20000).Send(sendQueue, sentMsg)
+	assert.Nil(t, err)
+
+	requestConsumer, rConErr := context.CreateConsumer(requestQueue)
+	assert.Nil(t, rConErr)
+	if requestConsumer != nil {
+		defer requestConsumer.Close()
+	}
+
+	rcvMsg, err := requestConsumer.ReceiveNoWait()
+	assert.Nil(t, err)
+	assert.NotNil(t, rcvMsg)
+
+	replyDest := rcvMsg.GetJMSReplyTo().(jms20subset.Queue)
+	assert.Equal(t, "DEV.QUEUE.2", replyDest.GetQueueName())
+	assert.Equal(t, "REMOTE.QM", replyDest.GetQueueManagerName())
+
+	// A queue manager name that is too long is rejected.
+	longQueue := context.CreateQueue("DEV.QUEUE.1").SetQueueManagerName("THIS.QUEUE.MANAGER.NAME.IS.LONGER.THAN.FORTY.EIGHT.CHARACTERS")
+	assert.Equal(t, "", longQueue.GetQueueManagerName())
+
+}
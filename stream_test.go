@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) IBM Corporation 2026
+ *
+ * This program and the accompanying materials are made available under the
+ * terms of the Eclipse Public License v. 2.0, which is available at
+ * http://www.eclipse.org/legal/epl-2.0.
+ *
+ * SPDX-License-Identifier: EPL-2.0
+ */
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io"
+	"testing"
+
+	"github.com/ibm-messaging/mq-golang-jms20/jms20subset"
+	"github.com/ibm-messaging/mq-golang-jms20/mqjms"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+ * Test sending and receiving a payload that is larger than the maximum message
+ * length of the queue, by streaming it in segments.
+ */
+func TestStreamSendReceive(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	// A payload of 5MB, which is larger than the default MAXMSGL of 4MB.
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 5*1024*1024/16)
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	errSend := context.CreateProducer().SetTimeToLive(60000).SendStream(queue, bytes.NewReader(payload))
+	assert.Nil(t, errSend)
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// The segments are reassembled, even though each is larger than the
+	// default receive buffer.
+	reader, errRcv := consumer.ReceiveStream(1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, reader)
+
+	received, readErr := io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, len(payload), len(received))
+	assert.True(t, bytes.Equal(payload, received))
+
+	// The whole message was consumed.
+	reader, errRcv = consumer.ReceiveStream(100)
+	assert.Nil(t, errRcv)
+	assert.Nil(t, reader)
+
+}
+
+/*
+ * Test that closing a stream before it has been read completely leaves the
+ * message on the queue, and that ordinary messages can be read as a stream.
+ */
+func TestStreamPartialRead(t *testing.T) {
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	payload := bytes.Repeat([]byte("x"), 3*1024*1024)
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+	producer := context.CreateProducer().SetTimeToLive(60000)
+	errSend := producer.SendStream(queue, bytes.NewReader(payload))
+	assert.Nil(t, errSend)
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Read only part of the first segment, then close the stream.
+	reader, errRcv := consumer.ReceiveStream(1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, reader)
+
+	partial := make([]byte, 1024)
+	_, readErr := io.ReadFull(reader, partial)
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+
+	// The message is still available in full.
+	reader, errRcv = consumer.ReceiveStream(1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, reader)
+
+	received, readErr := io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+	assert.True(t, bytes.Equal(payload, received))
+
+	// A message that was not sent as a stream is returned as a single segment.
+	errSend = producer.SendString(queue, "not a stream")
+	assert.Nil(t, errSend)
+
+	reader, errRcv = consumer.ReceiveStream(1000)
+	assert.Nil(t, errRcv)
+	assert.NotNil(t, reader)
+
+	received, readErr = io.ReadAll(reader)
+	assert.Nil(t, readErr)
+	assert.Nil(t, reader.Close())
+	assert.Equal(t, "not a stream", string(received))
+
+}
+
+/*
+ * Test that streams are rejected for a destination that has a protection policy,
+ * and by a producer that has compression enabled, as neither can be applied to a
+ * stream.
+ */
+func TestStreamProtected(t *testing.T) {
+
+	senderKey, senderCert := createProtectionIdentity(t, "sender")
+
+	// Loads CF parameters from connection_info.json and applicationApiKey.json in the Downloads directory
+	cf, cfErr := mqjms.CreateConnectionFactoryFromDefaultJSONFiles()
+	assert.Nil(t, cfErr)
+
+	cf.ProtectionPolicies = map[string]mqjms.ProtectionPolicy{
+		"DEV.QUEUE.1": {
+			QualityOfProtection: mqjms.ProtectionPolicy_QOP_INTEGRITY,
+			SigningCertificate:  senderCert,
+			SigningKey:          senderKey,
+			TrustedSigners:      []*x509.Certificate{senderCert},
+		},
+	}
+
+	// Creates a connection to the queue manager, using defer to close it automatically
+	// at the end of the function (if it was created successfully)
+	context, ctxErr := cf.CreateContext()
+	assert.Nil(t, ctxErr)
+	if context != nil {
+		defer context.Close()
+	}
+
+	queue := context.CreateQueue("DEV.QUEUE.1")
+
+	// The stream is not sent to the protected queue.
+	errSend := context.CreateProducer().SetTimeToLive(10000).SendStream(queue, bytes.NewReader([]byte("protected stream")))
+	assert.NotNil(t, errSend)
+	if errSend != nil {
+		assert.Equal(t, "ProtectionNotSupported", errSend.GetErrorCode())
+	}
+
+	consumer, errCons := context.CreateConsumer(queue)
+	assert.Nil(t, errCons)
+	if consumer != nil {
+		defer consumer.Close()
+	}
+
+	// Nor can it be received from the protected queue.
+	reader, errRcv := consumer.ReceiveStream(100)
+	assert.Nil(t, reader)
+	assert.NotNil(t, errRcv)
+	if errRcv != nil {
+		assert.Equal(t, "ProtectionNotSupported", errRcv.GetErrorCode())
+	}
+
+	// Nothing was sent to the queue.
+	rcvMsg, errRcv := consumer.ReceiveNoWait()
+	assert.Nil(t, errRcv)
+	assert.Nil(t, rcvMsg)
+
+	// A stream can't be compressed either, even when it isn't protected.
+	unprotectedQueue := context.CreateQueue("DEV.QUEUE.2")
+	errSend = context.CreateProducer().SetCompression(jms20subset.Compression_GZIP).SendStream(unprotectedQueue, bytes.NewReader([]byte("compressed stream")))
+	assert.NotNil(t, errSend)
+	if errSend != nil {
+		assert.Equal(t, "CompressionNotSupported", errSend.GetErrorCode())
+	}
+
+}